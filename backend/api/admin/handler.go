@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// AdminPath is the prefix of the routes which manage the API itself.
+	AdminPath = "/admin"
+
+	// ConfigReloadPath reads the configuration file again, applying the settings which can change at runtime.
+	// /admin/config/reload
+	ConfigReloadPath = "/config/reload"
+)
+
+// ReloadConfig returns the handler which reloads the configuration with reload.
+func ReloadConfig(reload ReloadFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		restart, err := reload()
+		if err != nil {
+			utils.ErrRes(c, utils.NewProblemError(InvalidConfig, err), http.StatusUnprocessableEntity)
+			return
+		}
+
+		c.Negotiate(http.StatusOK, gin.Negotiate{
+			Offered: utils.Negotiate,
+			Data:    ConfigReload{ReloadedAt: time.Now().UTC(), Restart: restart},
+		})
+	}
+}
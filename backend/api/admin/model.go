@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+)
+
+// InvalidConfig is used when the configuration reloaded is not valid, so the current one is kept.
+var InvalidConfig = utils.ProblemType{
+	Slug: "invalid_config", Title: "The configuration is not valid, the current one is kept", Status: http.StatusUnprocessableEntity,
+}
+
+// ReloadFunc reloads the configuration, returning the settings which changed but need a restart
+// to be applied.
+type ReloadFunc func() (restart []string, err error)
+
+// ConfigReload
+//
+// It is the result of reloading the configuration.
+//
+// swagger:model config-reload
+type ConfigReload struct {
+	// swagger:ignore
+	XMLName xml.Name `json:"-" yaml:"-" xml:"ConfigReload"`
+	// ReloadedAt is when the configuration was reloaded
+	ReloadedAt time.Time `json:"reloaded_at" yaml:"reloaded_at" xml:"ReloadedAt"`
+	// Restart are the settings which changed but are only applied when the API is restarted
+	Restart []string `json:"restart,omitempty" yaml:"restart,omitempty" xml:"Restart>i,omitempty"`
+}
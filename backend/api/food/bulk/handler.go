@@ -0,0 +1,75 @@
+package bulk
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ImportPath imports the catalog of the body, encoded as CSV, JSON or YAML depending on the Content-Type.
+	// /food/import?mode=upsert
+	ImportPath = "/import"
+	// ExportPath exports the catalog which is not in the trash.
+	// /food/export?format=csv
+	ExportPath = "/export"
+
+	// FormatParam is the format of the catalog exported. It is JSON by default.
+	FormatParam = "format"
+)
+
+func ImportCatalog(c *gin.Context) {
+	mode, err := utils.ParseModeQuery(c, utils.Insert)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	format, err := FormatOfContentType(c.ContentType())
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	records, err := Decode(format, c.Request.Body)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	report, err := Import(c.Request.Context(), records, mode)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    report,
+	})
+}
+
+func ExportCatalog(c *gin.Context) {
+	format, err := ParseFormat(c.DefaultQuery(FormatParam, string(JSON)))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	records, err := Export(c.Request.Context())
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	var body bytes.Buffer
+	if err = Encode(format, &body, records); err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="catalog.`+string(format)+`"`)
+	c.Data(http.StatusOK, format.ContentType(), body.Bytes())
+}
@@ -0,0 +1,362 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of the catalog files.
+type Format string
+
+const (
+	// CSV files have a row per resource, with the columns of csvHeader.
+	CSV Format = "csv"
+	// JSON files have the tree of categories, subcategories, units and varieties.
+	JSON Format = "json"
+	// YAML files have the tree of categories, subcategories, units and varieties.
+	YAML Format = "yaml"
+
+	// MIMECSV is the Content-Type of the CSV files.
+	MIMECSV = "text/csv"
+	// MIMEYAML is the Content-Type of the YAML files.
+	MIMEYAML = "application/yaml"
+)
+
+var (
+	// Formats are all the formats allowed.
+	Formats = []Format{CSV, JSON, YAML}
+
+	// ErrUnsupportedFormat is used when the format of a catalog file is not CSV, JSON nor YAML.
+	ErrUnsupportedFormat = errors.New("catalog must be csv, json or yaml")
+	// ErrInvalidHeader is used when the first row of a CSV file is not csvHeader.
+	ErrInvalidHeader = errors.New("csv header must be " + strings.Join(csvHeader, ","))
+	// ErrUnknownType is used when the type of a resource is not one of the hierarchy.
+	ErrUnknownType = errors.New("type must be category, subcategory, unit or variety")
+	// ErrParentRequired is used when a resource other than a category has no parent.
+	ErrParentRequired = errors.New("parent is required, except for the categories")
+
+	// csvHeader are the columns of the CSV files. The parent is the name of the category of a
+	// subcategory, the subcategory of a unit or the unit of a variety. Only varieties have img.
+	csvHeader = []string{"type", "name", "description", "parent", "img"}
+
+	// contentTypes are the Content-Type of each format. The first one is used when exporting.
+	contentTypes = map[Format][]string{
+		CSV:  {MIMECSV, "application/csv"},
+		JSON: {"application/json"},
+		YAML: {MIMEYAML, "application/x-yaml", "text/yaml", "text/x-yaml"},
+	}
+)
+
+// ParseFormat returns the format named s, which can also be the yml extension.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, JSON, YAML:
+		return f, nil
+	case "yml":
+		return YAML, nil
+	}
+
+	return "", utils.NewProblemError(utils.BadRequest, fmt.Errorf("%w, got %q", ErrUnsupportedFormat, s))
+}
+
+// FormatOf returns the format of the file path taken from its extension.
+func FormatOf(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// FormatOfContentType returns the format whose Content-Type is ct.
+func FormatOfContentType(ct string) (Format, error) {
+	for _, f := range Formats {
+		for _, each := range contentTypes[f] {
+			if each == ct {
+				return f, nil
+			}
+		}
+	}
+
+	return "", utils.ErrContentTypeNotAllowed
+}
+
+// ContentType returns the Content-Type of the files of the format f.
+func (f Format) ContentType() string {
+	return contentTypes[f][0]
+}
+
+// Record is each of the resources of a catalog file, which are found by name.
+type Record struct {
+	// Row is the path of the resource inside of the file, used to report its errors.
+	Row         string
+	Type        string
+	Name        string
+	Description string
+	Parent      string
+	Img         string
+}
+
+// Catalog is the content of the JSON and YAML files: the categories along with their
+// subcategories, units and varieties.
+type Catalog struct {
+	Categories []Category `json:"categories" yaml:"categories"`
+}
+
+// Category is a category of a catalog along with its subcategories.
+type Category struct {
+	Name          string        `json:"name" yaml:"name"`
+	Description   string        `json:"description" yaml:"description"`
+	Subcategories []Subcategory `json:"subcategories,omitempty" yaml:"subcategories,omitempty"`
+}
+
+// Subcategory is a subcategory of a catalog along with its units.
+type Subcategory struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Units       []Unit `json:"units,omitempty" yaml:"units,omitempty"`
+}
+
+// Unit is a unit of a catalog along with its varieties.
+type Unit struct {
+	Name        string    `json:"name" yaml:"name"`
+	Description string    `json:"description" yaml:"description"`
+	Varieties   []Variety `json:"varieties,omitempty" yaml:"varieties,omitempty"`
+}
+
+// Variety is a variety of a catalog.
+type Variety struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Img         string `json:"img" yaml:"img"`
+}
+
+// Records returns the resources of the catalog, each parent before its children.
+func (c Catalog) Records() []Record {
+	var records []Record
+
+	for i, ci := range c.Categories {
+		row := fmt.Sprintf("categories[%d]", i)
+		records = append(records, Record{Row: row, Type: kind(0), Name: ci.Name, Description: ci.Description})
+
+		for j, sj := range ci.Subcategories {
+			row := fmt.Sprintf("%s.subcategories[%d]", row, j)
+			records = append(records, Record{Row: row, Type: kind(1), Name: sj.Name, Description: sj.Description, Parent: ci.Name})
+
+			for k, uk := range sj.Units {
+				row := fmt.Sprintf("%s.units[%d]", row, k)
+				records = append(records, Record{Row: row, Type: kind(2), Name: uk.Name, Description: uk.Description, Parent: sj.Name})
+
+				for l, vl := range uk.Varieties {
+					row := fmt.Sprintf("%s.varieties[%d]", row, l)
+					records = append(records, Record{
+						Row: row, Type: kind(3), Name: vl.Name, Description: vl.Description, Parent: uk.Name, Img: vl.Img,
+					})
+				}
+			}
+		}
+	}
+
+	return records
+}
+
+// NewCatalog returns the tree of records, keeping their order. The records whose parent is not
+// among them are left out.
+func NewCatalog(records []Record) Catalog {
+	var (
+		catalog       Catalog
+		subcategories = make(map[string][]Subcategory)
+		units         = make(map[string][]Unit)
+		varieties     = make(map[string][]Variety)
+	)
+
+	for _, each := range records {
+		if level(each.Type) == 3 {
+			varieties[each.Parent] = append(varieties[each.Parent], Variety{Name: each.Name, Description: each.Description, Img: each.Img})
+		}
+	}
+
+	for _, each := range records {
+		if level(each.Type) == 2 {
+			units[each.Parent] = append(units[each.Parent], Unit{
+				Name: each.Name, Description: each.Description, Varieties: varieties[each.Name],
+			})
+		}
+	}
+
+	for _, each := range records {
+		if level(each.Type) == 1 {
+			subcategories[each.Parent] = append(subcategories[each.Parent], Subcategory{
+				Name: each.Name, Description: each.Description, Units: units[each.Name],
+			})
+		}
+	}
+
+	for _, each := range records {
+		if level(each.Type) == 0 {
+			catalog.Categories = append(catalog.Categories, Category{
+				Name: each.Name, Description: each.Description, Subcategories: subcategories[each.Name],
+			})
+		}
+	}
+
+	return catalog
+}
+
+// Report
+//
+// It is the result of an import, with the counts of each kind of resource.
+//
+// swagger:model import-report
+type Report struct {
+	// swagger:ignore
+	XMLName xml.Name `json:"-" yaml:"-" xml:"Import"`
+	// Mode is how the resources which already existed were handled
+	//
+	// enum: insert,upsert,replace
+	Mode          utils.Mode   `json:"mode" yaml:"mode" xml:"mode,attr"`
+	Categories    utils.Counts `json:"categories" yaml:"categories" xml:"Categories"`
+	Subcategories utils.Counts `json:"subcategories" yaml:"subcategories" xml:"Subcategories"`
+	Units         utils.Counts `json:"units" yaml:"units" xml:"Units"`
+	Varieties     utils.Counts `json:"varieties" yaml:"varieties" xml:"Varieties"`
+}
+
+// Of returns the counts of the level of the hierarchy.
+func (r *Report) Of(level int) *utils.Counts {
+	return [...]*utils.Counts{&r.Categories, &r.Subcategories, &r.Units, &r.Varieties}[level]
+}
+
+// ReadFile returns the records of the catalog file path, whose format is taken from its extension.
+func ReadFile(path string) ([]Record, error) {
+	f, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(f, bytes.NewReader(content))
+}
+
+// Decode returns the records of the catalog read from r, encoded as f. Unknown fields are
+// rejected, so typos don't go unnoticed. The CSV rows are reported as rows[i], being 0 the
+// first row after the header.
+func Decode(f Format, r io.Reader) (records []Record, err error) {
+	var catalog Catalog
+
+	switch f {
+	case CSV:
+		records, err = decodeCSV(r)
+	case JSON:
+		d := json.NewDecoder(r)
+		d.DisallowUnknownFields()
+		err = d.Decode(&catalog)
+		records = catalog.Records()
+	case YAML:
+		d := yaml.NewDecoder(r)
+		d.KnownFields(true)
+		err = d.Decode(&catalog)
+		records = catalog.Records()
+	default:
+		err = ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return nil, utils.NewProblemError(utils.BadRequest, fmt.Errorf("decoding %s catalog: %w", f, err))
+	}
+
+	return records, nil
+}
+
+// Encode writes records into w encoded as f.
+func Encode(f Format, w io.Writer, records []Record) error {
+	switch f {
+	case CSV:
+		return encodeCSV(w, records)
+	case JSON:
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(NewCatalog(records))
+	case YAML:
+		e := yaml.NewEncoder(w)
+		e.SetIndent(2)
+		if err := e.Encode(NewCatalog(records)); err != nil {
+			return err
+		}
+		return e.Close()
+	}
+
+	return ErrUnsupportedFormat
+}
+
+func decodeCSV(r io.Reader) ([]Record, error) {
+	var (
+		records []Record
+		cr      = csv.NewReader(r)
+	)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	} else if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+		return nil, ErrInvalidHeader
+	}
+
+	for i := 0; ; i++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		records = append(records, Record{
+			Row: fmt.Sprintf("rows[%d]", i), Type: row[0], Name: row[1], Description: row[2], Parent: row[3], Img: row[4],
+		})
+	}
+}
+
+func encodeCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, each := range records {
+		if err := cw.Write([]string{each.Type, each.Name, each.Description, each.Parent, each.Img}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// level returns the index of the hierarchy of the kind of resource, or -1 if it is unknown.
+func level(kind string) int {
+	for i, each := range ca.Hierarchy {
+		if each.Kind == kind {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// kind returns the kind of resource of the level of the hierarchy.
+func kind(level int) string {
+	return ca.Hierarchy[level].Kind
+}
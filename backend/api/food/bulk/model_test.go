@@ -0,0 +1,157 @@
+package bulk
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalog has two categories, the first one with the whole hierarchy below it.
+var catalog = Catalog{Categories: []Category{
+	{Name: "fruits", Description: "sweet", Subcategories: []Subcategory{
+		{Name: "citrus", Description: "sour", Units: []Unit{
+			{Name: "lemon", Description: "yellow", Varieties: []Variety{
+				{Name: "eureka", Description: "common, \"all year\"", Img: "eureka.png"},
+				{Name: "meyer", Description: "sweeter"},
+			}},
+		}},
+	}},
+	{Name: "vegetables", Description: "green"},
+}}
+
+func TestRecords(t *testing.T) {
+	assert.Equal(t, []Record{
+		{Row: "categories[0]", Type: "category", Name: "fruits", Description: "sweet"},
+		{Row: "categories[0].subcategories[0]", Type: "subcategory", Name: "citrus", Description: "sour", Parent: "fruits"},
+		{Row: "categories[0].subcategories[0].units[0]", Type: "unit", Name: "lemon", Description: "yellow", Parent: "citrus"},
+		{
+			Row: "categories[0].subcategories[0].units[0].varieties[0]", Type: "variety", Name: "eureka",
+			Description: "common, \"all year\"", Parent: "lemon", Img: "eureka.png",
+		},
+		{Row: "categories[0].subcategories[0].units[0].varieties[1]", Type: "variety", Name: "meyer", Description: "sweeter", Parent: "lemon"},
+		{Row: "categories[1]", Type: "category", Name: "vegetables", Description: "green"},
+	}, catalog.Records())
+}
+
+func TestNewCatalog(t *testing.T) {
+	records := append(catalog.Records(), Record{Type: "unit", Name: "orphan", Parent: "missing"})
+
+	assert.Equal(t, catalog, NewCatalog(records))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, each := range Formats {
+		t.Run(string(each), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			require.NoError(t, Encode(each, &buf, catalog.Records()))
+
+			got, err := Decode(each, &buf)
+			require.NoError(t, err)
+			assert.Equal(t, catalog, NewCatalog(got))
+
+			if each == CSV {
+				for i, record := range got {
+					assert.Equal(t, fmt.Sprintf("rows[%d]", i), record.Row)
+				}
+			} else {
+				assert.Equal(t, catalog.Records(), got)
+			}
+		})
+	}
+}
+
+func TestDecodeCSV(t *testing.T) {
+	got, err := Decode(CSV, strings.NewReader("type,name,description,parent,img\n"+
+		"category,fruits,sweet,,\n"+
+		"variety,eureka,\"common, \"\"all year\"\"\",lemon,eureka.png\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Record{
+		{Row: "rows[0]", Type: "category", Name: "fruits", Description: "sweet"},
+		{Row: "rows[1]", Type: "variety", Name: "eureka", Description: "common, \"all year\"", Parent: "lemon", Img: "eureka.png"},
+	}, got)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		format      Format
+		input       string
+		wantErr     error
+	}{
+		{
+			description: "csv with another header",
+			format:      CSV,
+			input:       "kind,name\ncategory,fruits\n",
+			wantErr:     ErrInvalidHeader,
+		},
+		{
+			description: "csv row with missing columns",
+			format:      CSV,
+			input:       "type,name,description,parent,img\ncategory,fruits\n",
+		},
+		{
+			description: "json with unknown field",
+			format:      JSON,
+			input:       `{"categories":[{"name":"fruits","colour":"red"}]}`,
+		},
+		{
+			description: "yaml with unknown field",
+			format:      YAML,
+			input:       "categories:\n- name: fruits\n  colour: red\n",
+		},
+		{
+			description: "unsupported format",
+			format:      "xml",
+			wantErr:     ErrUnsupportedFormat,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var pErr *utils.ProblemError
+
+			_, err := Decode(each.format, strings.NewReader(each.input))
+
+			require.ErrorAs(t, err, &pErr)
+			assert.Equal(t, utils.BadRequest, pErr.Type)
+			if each.wantErr != nil {
+				assert.ErrorIs(t, err, each.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormats(t *testing.T) {
+	for _, each := range []struct {
+		input string
+		want  Format
+	}{
+		{input: "CSV", want: CSV},
+		{input: "json", want: JSON},
+		{input: "yml", want: YAML},
+	} {
+		got, err := ParseFormat(each.input)
+		assert.NoError(t, err)
+		assert.Equal(t, each.want, got)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	got, err := FormatOf("testdata/catalog.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, YAML, got)
+
+	got, err = FormatOfContentType("application/csv")
+	assert.NoError(t, err)
+	assert.Equal(t, CSV, got)
+	assert.Equal(t, MIMECSV, got.ContentType())
+
+	_, err = FormatOfContentType("application/xml")
+	assert.ErrorIs(t, err, utils.ErrContentTypeNotAllowed)
+}
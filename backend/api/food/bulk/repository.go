@@ -0,0 +1,191 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
+	u "github.com/MrTimeout/go-home/backend/api/food/unit"
+	v "github.com/MrTimeout/go-home/backend/api/food/variety"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"gorm.io/gorm"
+)
+
+// Import saves records, from the categories to the varieties, using the same validations as the API.
+// It runs in a single transaction: if any of the records fails, nothing is saved and the error is a
+// utils.RowErrors with the error of each record. The children of a record which failed are skipped.
+// In utils.Replace mode, the resources which are not among records are moved to the trash first.
+func Import(ctx context.Context, records []Record, mode utils.Mode) (report Report, err error) {
+	records = append([]Record(nil), records...)
+	sort.SliceStable(records, func(i, j int) bool { return level(records[i].Type) < level(records[j].Type) })
+
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			ctx     = config.WithTransaction(ctx, tx)
+			failed  = make([]map[string]bool, len(ca.Hierarchy))
+			rowErrs utils.RowErrors
+		)
+
+		if mode == utils.Replace {
+			if err := trashMissing(ctx, tx, records, &report); err != nil {
+				return err
+			}
+		}
+
+		for _, each := range records {
+			l := level(each.Type)
+
+			switch {
+			case l < 0:
+				rowErrs = append(rowErrs, utils.RowError{Row: each.Row, Err: fmt.Errorf("%w, got %q", ErrUnknownType, each.Type)})
+				continue
+			case l > 0 && each.Parent == "":
+				rowErrs = append(rowErrs, utils.RowError{Row: each.Row, Err: ErrParentRequired})
+				continue
+			case l > 0 && failed[l-1][each.Parent]:
+				failed[l] = addName(failed[l], each.Name)
+				continue
+			}
+
+			outcome, err := upsert(ctx, l, each, mode)
+			if err != nil {
+				failed[l] = addName(failed[l], each.Name)
+				rowErrs = append(rowErrs, utils.RowError{Row: each.Row, Err: err})
+				continue
+			}
+
+			report.Of(l).Add(outcome)
+		}
+
+		if len(rowErrs) > 0 {
+			return rowErrs
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	report.Mode = mode
+
+	return report, nil
+}
+
+// Export returns the resources which are not in the trash, each parent before its children
+// and ordered by name inside of each level.
+func Export(ctx context.Context) ([]Record, error) {
+	var (
+		records []Record
+		db      = config.GetInstance(ctx)
+	)
+
+	for i, each := range ca.Hierarchy {
+		var (
+			rows    []Record
+			columns = []string{"'" + each.Kind + "' AS type", "r.name", "r.description"}
+			tx      = db.Table(each.Table + " AS r").Where(utils.TrashCondition("r", false))
+		)
+
+		if i > 0 {
+			parent := ca.Hierarchy[i-1]
+			tx = tx.Joins("JOIN " + parent.Table + " AS p USING(" + parent.Key + ")").Where(utils.TrashCondition("p", false))
+			columns = append(columns, "p.name AS parent")
+		}
+
+		if i == len(ca.Hierarchy)-1 {
+			columns = append(columns, "r.img")
+		}
+
+		if err := tx.Select(columns).Order("r.name").Find(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		records = append(records, rows...)
+	}
+
+	return records, nil
+}
+
+// upsert saves the record of the level of the hierarchy with the repository function of its kind.
+func upsert(ctx context.Context, level int, r Record, mode utils.Mode) (utils.Outcome, error) {
+	switch level {
+	case 0:
+		return ca.UpsertCategory(ctx, ca.FoodCategory{Name: r.Name, Description: r.Description}, mode)
+	case 1:
+		return sca.UpsertSubcategory(ctx, sca.FoodSubcategory{
+			Name: r.Name, Description: r.Description, FoodCategory: ca.FoodCategory{Name: r.Parent},
+		}, mode)
+	case 2:
+		return u.UpsertUnit(ctx, u.FoodUnit{
+			Name: r.Name, Description: r.Description, FoodSubcategory: sca.FoodSubcategory{Name: r.Parent},
+		}, mode)
+	default:
+		return v.UpsertVariety(ctx, v.FoodUnitVariety{
+			Name: r.Name, Description: r.Description, Img: r.Img, FoodUnit: u.FoodUnit{Name: r.Parent},
+		}, mode)
+	}
+}
+
+// trashMissing moves to the trash the resources which are not among records, along with their
+// descendants. Only the ones missing are counted as deleted, because the descendants among records
+// are restored afterwards.
+func trashMissing(ctx context.Context, tx *gorm.DB, records []Record, report *Report) error {
+	names := make([]map[string]bool, len(ca.Hierarchy))
+	for _, each := range records {
+		if l := level(each.Type); l >= 0 {
+			names[l] = addName(names[l], each.Name)
+		}
+	}
+
+	for i, each := range ca.Hierarchy {
+		var nodes []utils.Node
+
+		txx := tx.Table(each.Table).Select(each.Key+" AS id", "name").Where(utils.TrashCondition(each.Table, false))
+		if len(names[i]) > 0 {
+			txx = txx.Where("name NOT IN ?", keys(names[i]))
+		}
+
+		if err := txx.Find(&nodes).Error; err != nil {
+			return err
+		} else if len(nodes) == 0 {
+			continue
+		}
+
+		result, err := utils.DeleteTree(tx, config.GetDrySession(ctx), ca.Hierarchy[i:], nodes, utils.DeleteOptions{Cascade: true})
+		if err != nil {
+			return err
+		}
+
+		for _, node := range result.Nodes {
+			if l := level(node.Kind); !names[l][node.Name] {
+				report.Of(l).Add(utils.Deleted)
+			}
+		}
+	}
+
+	return nil
+}
+
+// addName adds name to set, creating it if it is nil.
+func addName(set map[string]bool, name string) map[string]bool {
+	if set == nil {
+		set = make(map[string]bool)
+	}
+
+	set[name] = true
+
+	return set
+}
+
+func keys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for each := range set {
+		result = append(result, each)
+	}
+
+	return result
+}
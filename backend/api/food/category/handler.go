@@ -1,8 +1,8 @@
 package category
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/MrTimeout/go-home/backend/api/utils"
 	"github.com/gin-gonic/gin"
@@ -16,28 +16,52 @@ const (
 	// return the category by name.
 	// /food/categories/:category-name
 	CategoryByNamePath = CategoriesPath + "/:" + CategoryNameParam
+	// CategoryRestorePath restores the category from the trash.
+	// /food/categories/:category-name/restore
+	CategoryRestorePath = CategoryByNamePath + utils.RestorePathName
 
 	// CategoryNameParam is the category name value
 	CategoryNameParam = "category-name"
 )
 
+// ErrCategoryNotFound is returned when the category doesn't exist.
+var ErrCategoryNotFound = errors.New("category not found")
+
 func GetCategories(c *gin.Context) {
-	categories, err := getCategories(c.Request.Context(), utils.ParseRequest(c, FoodCategory{}))
+	wrap, err := utils.ParseRequest(c, FoodCategory{})
 	if err != nil {
-		utils.ErrRes(c, err, http.StatusNotFound)
+		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
-	c.Negotiate(http.StatusOK, gin.Negotiate{
-		Offered: utils.Negotiate,
-		Data:    categories,
-	})
+	categories, err := getCategories(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	total, err := countCategories(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.PageRes(c, wrap.NewPage(categories, total))
 }
 
 func GetCategoryByName(c *gin.Context) {
-	category, err := getCategories(c.Request.Context(), utils.ParseRequest(c, FoodCategory{Name: c.Param(CategoryNameParam)}))
+	wrap, err := utils.ParseRequest(c, FoodCategory{Name: c.Param(CategoryNameParam)})
 	if err != nil {
-		utils.ErrRes(c, err, http.StatusNotFound)
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	category, err := getCategories(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	} else if len(category) == 0 {
+		utils.ErrRes(c, ErrCategoryNotFound, http.StatusNotFound)
 		return
 	}
 
@@ -49,16 +73,57 @@ func GetCategoryByName(c *gin.Context) {
 
 func AddCategory(c *gin.Context) {
 	var category FoodCategory
-	if err := c.Bind(&category); err != nil {
+	if err := c.ShouldBind(&category); err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
 	if _, err := addCategory(c.Request.Context(), &category); err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    category,
+	})
+}
+
+func UpdateCategory(c *gin.Context) {
+	var category FoodCategory
+	if err := c.ShouldBind(&category); err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
+	updateCategoryRes(c, func(FoodCategory) (FoodCategory, error) {
+		return category, nil
+	})
+}
+
+func PatchCategory(c *gin.Context) {
+	patch, err := utils.MergePatchBody[FoodCategory](c)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	updateCategoryRes(c, func(fc FoodCategory) (FoodCategory, error) {
+		return utils.ApplyMergePatch(fc, patch)
+	})
+}
+
+func updateCategoryRes(c *gin.Context, update func(FoodCategory) (FoodCategory, error)) {
+	category, err := updateCategory(c.Request.Context(), FoodCategory{Name: c.Param(CategoryNameParam)}, update)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrCategoryNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		utils.ErrRes(c, err, statusCode)
+		return
+	}
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered: utils.Negotiate,
 		Data:    category,
@@ -66,20 +131,37 @@ func AddCategory(c *gin.Context) {
 }
 
 func DelCategory(c *gin.Context) {
-	var (
-		rows int64
-		err  error
-	)
-	if rows, err = delCategory(c.Request.Context(), FoodCategory{Name: c.Param(CategoryNameParam)}); err != nil {
+	opts, err := utils.ParseDeleteOptions(c)
+	if err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
+	result, err := delCategory(c.Request.Context(), FoodCategory{Name: c.Param(CategoryNameParam)}, opts)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrCategoryNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		utils.ErrRes(c, utils.DeleteErr(err), statusCode)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    result,
+	})
+}
+
+func RestoreCategory(c *gin.Context) {
+	result, err := restoreCategory(c.Request.Context(), FoodCategory{Name: c.Param(CategoryNameParam)})
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered: utils.Negotiate,
-		Data: utils.WrapperResponse{
-			Msg:  "category rows deleted " + strconv.Itoa(int(rows)),
-			Code: http.StatusOK,
-		},
+		Data:    result,
 	})
 }
@@ -0,0 +1,75 @@
+package category
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchCategoryMalformed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.PATCH(CategoryByNamePath, PatchCategory)
+
+	for _, each := range []struct {
+		description, body string
+		wantStatus        int
+		wantType          utils.ProblemType
+	}{
+		{description: "malformed json", body: `{"name":`, wantStatus: http.StatusBadRequest, wantType: utils.BadRequest},
+		{description: "array instead of object", body: `[1]`, wantStatus: http.StatusBadRequest, wantType: utils.BadRequest},
+		{description: "name of another type", body: `{"name":5}`, wantStatus: http.StatusUnprocessableEntity, wantType: utils.ValidationFailed},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				rec = httptest.NewRecorder()
+				req = httptest.NewRequest(http.MethodPatch, "/categories/fruits", strings.NewReader(each.body))
+				got utils.Problem
+			)
+
+			req.Header.Set("Content-Type", utils.MIMEMergePatchJSON)
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, each.wantStatus, rec.Code)
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, each.wantType.URI(), got.Type)
+		})
+	}
+}
+
+func TestGetCategoriesBadRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET(CategoriesPath, GetCategories)
+
+	for _, each := range []struct {
+		description, query string
+		wantErr            error
+	}{
+		{description: "tampered cursor", query: "cursor=tampered", wantErr: utils.ErrInvalidCursor},
+		{description: "filter value of another type", query: "filter=id:gt:abc", wantErr: utils.ErrInvalidFilter},
+		{description: "filter field not allowed", query: "filter=deleted_at:eq:x", wantErr: utils.ErrInvalidFilter},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				rec = httptest.NewRecorder()
+				got utils.Problem
+			)
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/categories?"+each.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, utils.BadRequest.URI(), got.Type)
+			assert.Contains(t, got.Detail, each.wantErr.Error())
+		})
+	}
+}
@@ -1,6 +1,20 @@
 package category
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"gorm.io/gorm"
+)
+
+// Hierarchy are the levels of the food classification, from the categories to the varieties.
+// Each package deletes its resources using the levels from its own one to the varieties.
+var Hierarchy = []utils.Level{
+	{Kind: "category", Table: "food_categories", Key: "food_category_id"},
+	{Kind: "subcategory", Table: "food_subcategories", Key: "food_subcategory_id"},
+	{Kind: "unit", Table: "food_units", Key: "food_unit_id"},
+	{Kind: "variety", Table: "food_unit_varieties", Key: "food_unit_variety_id"},
+}
 
 // FoodCategory
 //
@@ -16,19 +30,28 @@ type FoodCategory struct {
 	//
 	// required: true
 	// min length: 2
+	// max length: 64
 	// example: grains
-	Name string `gorm:"column:name;not null;unique" json:"name" xml:"Name"`
+	Name string `gorm:"column:name;not null;uniqueIndex:food_categories_name_key,where:deleted_at IS NULL" json:"name" xml:"Name" binding:"required,min=2,max=64,trimmed,food_name"`
 	// The description of the category. It should not be so long.
 	//
 	// required: true
 	// min length: 10
+	// max length: 512
 	// example: a single fruit or seed of a cereal
-	Description string `gorm:"column:description;not null" json:"description" xml:"Description"`
+	Description string `gorm:"column:description;not null" json:"description" xml:"Description" binding:"required,min=10,max=512,trimmed"`
+	// swagger:ignore
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-" xml:"-"`
 }
 
-// OrderByColumnsAllowed will return the list of columns allowed to order by.
+// OrderByColumnsAllowed will return the list of fields allowed to order by, mapped to their columns.
 func (FoodCategory) OrderByColumnsAllowed() map[string]any {
-	return map[string]any{"id": struct{}{}, "name": struct{}{}}
+	return map[string]any{"id": "food_category_id", "name": "name"}
+}
+
+// FilterColumnsAllowed will return the list of fields allowed to filter by, mapped to their columns.
+func (FoodCategory) FilterColumnsAllowed() map[string]any {
+	return map[string]any{"id": "food_category_id", "name": "name", "description": "description"}
 }
 
 // TableName returns the name of table inside of the database.
@@ -13,22 +13,130 @@ func addCategory(ctx context.Context, fc *FoodCategory) (rows int64, err error)
 	return tx.RowsAffected, tx.Error
 }
 
-func delCategory(ctx context.Context, fc FoodCategory) (rows int64, err error) {
-	tx := config.GetInstance(ctx).Where(&fc).Delete(fc)
-	return tx.RowsAffected, tx.Error
+// UpsertCategory creates the category fc, or updates the one with the same name restoring it from
+// the trash if needed. It is used to load the catalog in bulk, so it can be run several times.
+// In utils.Insert mode, it fails with utils.ErrAlreadyExists instead of updating.
+func UpsertCategory(ctx context.Context, fc FoodCategory, mode utils.Mode) (outcome utils.Outcome, err error) {
+	if err = utils.Validate(fc); err != nil {
+		return outcome, err
+	}
+
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		var current FoodCategory
+
+		if txx := tx.Unscoped().Where("name = ?", fc.Name).Order(utils.ActiveFirst).Find(&current); txx.Error != nil {
+			return txx.Error
+		} else if current.ID == 0 {
+			outcome = utils.Created
+			return tx.Create(&fc).Error
+		} else if mode == utils.Insert {
+			return utils.ErrAlreadyExists
+		} else if current.Description == fc.Description && !current.DeletedAt.Valid {
+			outcome = utils.Unchanged
+			return nil
+		}
+
+		outcome = utils.Updated
+		current.Description, current.DeletedAt = fc.Description, gorm.DeletedAt{}
+
+		return tx.Unscoped().Model(&current).Select("description", "deleted_at").Updates(&current).Error
+	})
+
+	return outcome, err
+}
+
+// updateCategory replaces the category found by fc with the one returned by update, which
+// receives the current state of the category.
+func updateCategory(ctx context.Context, fc FoodCategory, update func(FoodCategory) (FoodCategory, error)) (FoodCategory, error) {
+	var current FoodCategory
+
+	err := config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		if txx := tx.Where(&fc).Find(&current); txx.Error != nil {
+			return txx.Error
+		} else if current.ID == 0 {
+			return ErrCategoryNotFound
+		}
+
+		next, err := update(current)
+		if err != nil {
+			return err
+		} else if err = utils.Validate(next); err != nil {
+			return err
+		}
+
+		current.Name, current.Description = next.Name, next.Description
+
+		return tx.Model(&current).Select("name", "description").Updates(&current).Error
+	})
+
+	return current, err
+}
+
+// delCategory moves the categories found by fc to the trash, along with their subcategories, units and varieties
+// if opts.Cascade is true.
+func delCategory(ctx context.Context, fc FoodCategory, opts utils.DeleteOptions) (result utils.DeleteResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := categoryNodes(tx, fc, false)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return ErrCategoryNotFound
+		}
+
+		result, err = utils.DeleteTree(tx, config.GetDrySession(ctx), Hierarchy, roots, opts)
+		return err
+	})
+
+	return result, err
+}
+
+// restoreCategory restores the categories found by fc from the trash, along with the subcategories, units and varieties
+// deleted at the same time.
+func restoreCategory(ctx context.Context, fc FoodCategory) (result utils.RestoreResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := categoryNodes(tx, fc, true)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return utils.ErrNotInTrash
+		}
+
+		result, err = utils.RestoreTree(tx, Hierarchy, roots)
+		return err
+	})
+
+	return result, err
+}
+
+// categoryNodes returns the categories found by fc which are in the trash if deleted is true,
+// or the ones which are not otherwise.
+func categoryNodes(tx *gorm.DB, fc FoodCategory, deleted bool) ([]utils.Node, error) {
+	var nodes []utils.Node
+
+	tx = WhereCategories(tx.Table(fc.TableName()), fc).
+		Where(utils.TrashCondition(fc.TableName(), deleted)).
+		Select(fc.TableName()+".food_category_id AS id", fc.TableName()+".name", fc.TableName()+".deleted_at").
+		Find(&nodes)
+
+	return nodes, tx.Error
 }
 
 func getCategories(ctx context.Context, wrap utils.WrapperRequest[FoodCategory]) ([]FoodCategory, error) {
 	var result []FoodCategory
 
-	tx := wrap.ToScope(config.GetInstance(ctx).Where(wrap.Body)).Find(&result)
+	tx := WhereCategories(wrap.ToScope(config.GetInstance(ctx)), wrap.Body).Find(&result)
 
 	return result, tx.Error
 }
 
+func countCategories(ctx context.Context, wrap utils.WrapperRequest[FoodCategory]) (total int64, err error) {
+	tx := WhereCategories(wrap.ToFilter(config.GetInstance(ctx).Model(&FoodCategory{})), wrap.Body).Count(&total)
+	return total, tx.Error
+}
+
 func WhereCategories(db *gorm.DB, fc FoodCategory) *gorm.DB {
 	if fc.ID != 0 {
-		db = db.Where(fc.TableName()+".id = ?", fc.ID)
+		db = db.Where(fc.TableName()+".food_category_id = ?", fc.ID)
 	}
 
 	if fc.Description != "" {
@@ -0,0 +1,51 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SearchPath searches the text inside of all the catalog.
+	// /food/search?q=granny
+	SearchPath = "/search"
+
+	// QueryParam is the text to search
+	QueryParam = "q"
+	// TypeParam is the type of the hits to search. It can be passed more than once.
+	TypeParam = "type"
+)
+
+// ErrEmptyQuery is returned when the text to search is empty.
+var ErrEmptyQuery = errors.New("query to search is empty")
+
+func Search(c *gin.Context) {
+	types, err := utils.ParseValues(c, TypeParam, Types)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	wrap, err := utils.ParseRequest(c, Query{Q: strings.TrimSpace(c.Query(QueryParam)), Types: types})
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	if wrap.Body.Q == "" {
+		utils.ErrRes(c, ErrEmptyQuery, http.StatusBadRequest)
+		return
+	}
+
+	hits, err := search(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.PageRes(c, utils.NewCountedPage(wrap, hits))
+}
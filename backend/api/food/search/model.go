@@ -0,0 +1,104 @@
+package search
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const (
+	// CategoryType is the type of the hits which are categories.
+	CategoryType = "category"
+	// SubcategoryType is the type of the hits which are subcategories.
+	SubcategoryType = "subcategory"
+	// UnitType is the type of the hits which are units.
+	UnitType = "unit"
+	// VarietyType is the type of the hits which are varieties.
+	VarietyType = "variety"
+
+	// pathSeparator separates each level of the hierarchy path of a hit.
+	pathSeparator = " > "
+)
+
+// Types are all the types of hits, sorted by their level in the hierarchy.
+var Types = []string{CategoryType, SubcategoryType, UnitType, VarietyType}
+
+// Query is the search requested by the client.
+type Query struct {
+	// Q is the text to search.
+	Q string
+	// Types are the types of hits to search. All of them are searched if it is empty.
+	Types []string
+}
+
+// OrderByColumnsAllowed returns no columns, hits are always ordered by rank.
+func (Query) OrderByColumnsAllowed() map[string]any {
+	return map[string]any{}
+}
+
+// Hit
+//
+// It is each of the resources of the catalog matching the search, along with its
+// position inside of the hierarchy.
+//
+// swagger:model search-hit
+type Hit struct {
+	// swagger:ignore
+	XMLName xml.Name `gorm:"-" json:"-" xml:"Hit"`
+	// Type is the kind of resource found
+	//
+	// enum: category,subcategory,unit,variety
+	// example: variety
+	Type string `gorm:"column:type" json:"type" xml:"Type"`
+	// Name of the resource found
+	//
+	// example: Granny Smith
+	Name string `gorm:"column:name" json:"name" xml:"Name"`
+	// Description of the resource found
+	//
+	// example: This Australian native was discovered in 1868 as a chance seedling
+	Description string `gorm:"column:description" json:"description" xml:"Description"`
+	// Category of the resource, or the resource itself if it is a category
+	//
+	// example: fruit
+	Category string `gorm:"column:category" json:"category" xml:"Category"`
+	// Subcategory of the resource, if any
+	//
+	// example: Whole fruit
+	Subcategory string `gorm:"column:subcategory" json:"subcategory,omitempty" xml:"Subcategory,omitempty"`
+	// Unit of the resource, if any
+	//
+	// example: apple
+	Unit string `gorm:"column:unit" json:"unit,omitempty" xml:"Unit,omitempty"`
+	// Variety of the resource, if any
+	//
+	// example: Granny Smith
+	Variety string `gorm:"column:variety" json:"variety,omitempty" xml:"Variety,omitempty"`
+	// Path is the hierarchy path of the resource
+	//
+	// example: fruit > Whole fruit > apple > Granny Smith
+	Path string `gorm:"-" json:"path" xml:"Path"`
+	// Rank is the relevance of the hit, the greater the better
+	//
+	// example: 0.8
+	Rank float64 `gorm:"column:rank" json:"rank" xml:"Rank"`
+	// swagger:ignore
+	Total int64 `gorm:"column:total" json:"-" xml:"-"`
+}
+
+// RowsTotal returns the amount of hits without pagination.
+func (h Hit) RowsTotal() int64 {
+	return h.Total
+}
+
+// path returns the hierarchy path of the hit: category > subcategory > unit > variety
+func (h Hit) path() string {
+	var levels = make([]string, 0, len(Types))
+
+	for _, each := range []string{h.Category, h.Subcategory, h.Unit, h.Variety} {
+		if each != "" {
+			levels = append(levels, each)
+		}
+	}
+
+	return strings.Join(levels, pathSeparator)
+}
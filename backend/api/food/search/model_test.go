@@ -0,0 +1,25 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHitPath(t *testing.T) {
+	for _, each := range []struct {
+		input Hit
+		want  string
+	}{
+		{input: Hit{Type: CategoryType, Category: "fruit"}, want: "fruit"},
+		{input: Hit{Type: UnitType, Category: "fruit", Subcategory: "Whole fruit", Unit: "apple"}, want: "fruit > Whole fruit > apple"},
+		{
+			input: Hit{Type: VarietyType, Category: "fruit", Subcategory: "Whole fruit", Unit: "apple", Variety: "Granny Smith"},
+			want:  "fruit > Whole fruit > apple > Granny Smith",
+		},
+	} {
+		t.Run(each.input.Type, func(t *testing.T) {
+			assert.Equal(t, each.want, each.input.path())
+		})
+	}
+}
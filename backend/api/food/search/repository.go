@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+)
+
+// textSearchConfig is the PostgreSQL text search configuration used to build the tsvector
+// of each resource. It must be the same as in the indexes of the add_search_indexes migration
+// so the queries can use them.
+const textSearchConfig = "english"
+
+// document returns the expression of the text searched for each row of the table alias,
+// which is the same expression indexed by the add_search_indexes migration.
+func document(alias string) string {
+	if alias != "" {
+		alias += "."
+	}
+
+	return fmt.Sprintf("(%[1]sname || ' ' || %[1]sdescription)", alias)
+}
+
+// branch returns the query which searches the hits of one type, being levels the columns
+// with the names of the category, subcategory, unit and variety of each row. The rows in
+// the trash are skipped.
+func branch(kind, from, alias string, levels [4]string) string {
+	var (
+		doc      = document(alias)
+		tsvector = fmt.Sprintf("to_tsvector('%s', %s)", textSearchConfig, doc)
+		tsquery  = fmt.Sprintf("websearch_to_tsquery('%s', @q)", textSearchConfig)
+	)
+
+	for i := range levels {
+		if levels[i] == "" {
+			levels[i] = "''"
+		}
+	}
+
+	return fmt.Sprintf(
+		`SELECT '%s' AS type, %s.name AS name, %[2]s.description AS description, `+
+			`%s AS category, %s AS subcategory, %s AS unit, %s AS variety, `+
+			`ts_rank(%s, %s) + word_similarity(@q, %s) AS rank `+
+			`FROM %s WHERE (%[7]s @@ %[8]s OR @q <%% %[9]s) AND %[2]s.deleted_at IS NULL`,
+		kind, alias, levels[0], levels[1], levels[2], levels[3], tsvector, tsquery, doc, from,
+	)
+}
+
+// branches are the queries of each type of hit, joining the parents to know the hierarchy path.
+var branches = map[string]string{
+	CategoryType: branch(CategoryType, "food_categories AS ca", "ca",
+		[4]string{"ca.name"}),
+	SubcategoryType: branch(SubcategoryType, "food_subcategories AS sca JOIN food_categories AS ca USING(food_category_id)", "sca",
+		[4]string{"ca.name", "sca.name"}),
+	UnitType: branch(UnitType, "food_units AS u JOIN food_subcategories AS sca USING(food_subcategory_id) "+
+		"JOIN food_categories AS ca USING(food_category_id)", "u",
+		[4]string{"ca.name", "sca.name", "u.name"}),
+	VarietyType: branch(VarietyType, "food_unit_varieties AS v JOIN food_units AS u USING(food_unit_id) "+
+		"JOIN food_subcategories AS sca USING(food_subcategory_id) JOIN food_categories AS ca USING(food_category_id)", "v",
+		[4]string{"ca.name", "sca.name", "u.name", "v.name"}),
+}
+
+func search(ctx context.Context, wrap utils.WrapperRequest[Query]) ([]Hit, error) {
+	var (
+		result  []Hit
+		queries = make([]string, 0, len(Types))
+	)
+
+	for _, each := range Types {
+		if len(wrap.Body.Types) == 0 || utils.Contains(wrap.Body.Types, each) {
+			queries = append(queries, branches[each])
+		}
+	}
+
+	if len(queries) == 0 {
+		return result, nil
+	}
+
+	tx := config.GetInstance(ctx).Raw(
+		"SELECT hits.*, count(*) OVER () AS total FROM ("+strings.Join(queries, " UNION ALL ")+") AS hits "+
+			"ORDER BY hits.rank DESC, hits.type, hits.name LIMIT @limit OFFSET @skip",
+		map[string]any{"q": wrap.Body.Q, "limit": wrap.Limit, "skip": wrap.Skip},
+	).Scan(&result)
+
+	for i := range result {
+		result[i].Path = result[i].path()
+	}
+
+	return result, tx.Error
+}
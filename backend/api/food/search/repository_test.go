@@ -0,0 +1,96 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestSearch(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=home"), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+
+	var queries, statements []string
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:row", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+		queries = append(queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}))
+
+	ctx := config.WithTransaction(context.Background(), db)
+
+	run := func(t *testing.T, q string, types ...string) {
+		queries, statements = nil, nil
+
+		_, err := search(ctx, utils.WrapperRequest[Query]{Limit: 10, Skip: 20, Body: Query{Q: q, Types: types}})
+
+		assert.ErrorIs(t, err, gorm.ErrDryRunModeUnsupported)
+		require.Len(t, queries, 1)
+	}
+
+	t.Run("a single type is ranked by text search and similarity", func(t *testing.T) {
+		run(t, "apple", CategoryType)
+
+		assert.Equal(t, "SELECT hits.*, count(*) OVER () AS total FROM ("+
+			"SELECT 'category' AS type, ca.name AS name, ca.description AS description, "+
+			"ca.name AS category, '' AS subcategory, '' AS unit, '' AS variety, "+
+			"ts_rank(to_tsvector('english', (ca.name || ' ' || ca.description)), websearch_to_tsquery('english', 'apple')) + "+
+			"word_similarity('apple', (ca.name || ' ' || ca.description)) AS rank "+
+			"FROM food_categories AS ca "+
+			"WHERE (to_tsvector('english', (ca.name || ' ' || ca.description)) @@ websearch_to_tsquery('english', 'apple') "+
+			"OR 'apple' <% (ca.name || ' ' || ca.description)) AND ca.deleted_at IS NULL"+
+			") AS hits ORDER BY hits.rank DESC, hits.type, hits.name LIMIT 10 OFFSET 20", queries[0])
+	})
+
+	t.Run("all the types are joined with union all in hierarchy order", func(t *testing.T) {
+		run(t, "apple")
+
+		branches := strings.Split(queries[0], " UNION ALL ")
+		require.Len(t, branches, len(Types))
+		for i, each := range Types {
+			assert.Contains(t, branches[i], "SELECT '"+each+"' AS type")
+		}
+
+		assert.Contains(t, branches[3], "ca.name AS category, sca.name AS subcategory, u.name AS unit, v.name AS variety")
+		assert.Contains(t, branches[3], "FROM food_unit_varieties AS v JOIN food_units AS u USING(food_unit_id) "+
+			"JOIN food_subcategories AS sca USING(food_subcategory_id) JOIN food_categories AS ca USING(food_category_id)")
+		assert.Contains(t, branches[3], "v.deleted_at IS NULL")
+	})
+
+	t.Run("only the types requested are searched", func(t *testing.T) {
+		run(t, "apple", VarietyType, SubcategoryType)
+
+		branches := strings.Split(queries[0], " UNION ALL ")
+		require.Len(t, branches, 2)
+		assert.Contains(t, branches[0], "SELECT 'subcategory' AS type")
+		assert.Contains(t, branches[1], "SELECT 'variety' AS type")
+	})
+
+	t.Run("the text searched is bound, not interpolated", func(t *testing.T) {
+		run(t, "o'clock apple", UnitType)
+
+		assert.NotContains(t, statements[0], "clock")
+		assert.Contains(t, statements[0], "websearch_to_tsquery('english', $1)")
+	})
+
+	t.Run("unknown types search nothing", func(t *testing.T) {
+		queries = nil
+
+		got, err := search(ctx, utils.WrapperRequest[Query]{Limit: 10, Body: Query{Q: "apple", Types: []string{"recipe"}}})
+
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+		assert.Empty(t, queries)
+	})
+}
@@ -0,0 +1,24 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+
+	"github.com/MrTimeout/go-home/backend/api/food/bulk"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+)
+
+//go:embed catalog.yaml
+var defaultCatalog []byte
+
+// Default returns the records of the catalog embedded in the binary.
+func Default() ([]bulk.Record, error) {
+	return bulk.Decode(bulk.YAML, bytes.NewReader(defaultCatalog))
+}
+
+// Seed upserts records by name, so it can be run several times, as the resources already seeded
+// are left unchanged. Nothing is saved if any of the records fails.
+func Seed(ctx context.Context, records []bulk.Record) (bulk.Report, error) {
+	return bulk.Import(ctx, records, utils.Upsert)
+}
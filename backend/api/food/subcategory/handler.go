@@ -3,7 +3,6 @@ package subcategory
 import (
 	"errors"
 	"net/http"
-	"strconv"
 
 	ca "github.com/MrTimeout/go-home/backend/api/food/category"
 	"github.com/MrTimeout/go-home/backend/api/utils"
@@ -21,6 +20,9 @@ const (
 	// return the category by name.
 	// /food/categories/:category-name/subcategories/:subcategory-name
 	SubcategoryByNamePath = SubcategoriesPath + "/:" + SubcategoryNameParam
+	// SubcategoryRestorePath restores the subcategory from the trash.
+	// /food/categories/:category-name/subcategories/:subcategory-name/restore
+	SubcategoryRestorePath = SubcategoryByNamePath + utils.RestorePathName
 
 	// SubcategoryNameParam is the category name value
 	SubcategoryNameParam = "subcategory-name"
@@ -29,33 +31,40 @@ const (
 var ErrSubcategoryNotFound = errors.New("subcategory not found")
 
 func GetSubcategories(c *gin.Context) {
-	subcategories, err := getSubcategories(
-		c.Request.Context(),
-		utils.ParseRequest(c, newFoodSubcategoryFromParams(c)),
-	)
-	if err != nil || len(subcategories) == 0 {
-		if err == nil {
-			err = ErrSubcategoryNotFound
-		}
-		utils.ErrRes(c, err, http.StatusNotFound)
+	wrap, err := utils.ParseRequest(c, newFoodSubcategoryFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
-	c.Negotiate(http.StatusOK, gin.Negotiate{
-		Offered: utils.Negotiate,
-		Data:    subcategories,
-	})
+	subcategories, err := getSubcategories(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	total, err := countSubcategories(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.PageRes(c, wrap.NewPage(subcategories, total))
 }
 
 func GetSubcategoryByName(c *gin.Context) {
-	subcategory, err := getSubcategories(
-		c.Request.Context(),
-		utils.ParseRequest(c, newFoodSubcategoryFromParams(c)))
-	if err != nil || len(subcategory) == 0 {
-		if err == nil {
-			err = ErrSubcategoryNotFound
-		}
-		utils.ErrRes(c, err, http.StatusNotFound)
+	wrap, err := utils.ParseRequest(c, newFoodSubcategoryFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	subcategory, err := getSubcategories(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	} else if len(subcategory) == 0 {
+		utils.ErrRes(c, ErrSubcategoryNotFound, http.StatusNotFound)
 		return
 	}
 
@@ -67,7 +76,7 @@ func GetSubcategoryByName(c *gin.Context) {
 
 func AddSubcategory(c *gin.Context) {
 	var subcategory FoodSubcategory
-	if err := c.Bind(&subcategory); err != nil {
+	if err := c.ShouldBind(&subcategory); err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
@@ -75,10 +84,54 @@ func AddSubcategory(c *gin.Context) {
 	subcategory.FoodCategory.Name = c.Param(ca.CategoryNameParam)
 
 	if err := addSubcategory(c.Request.Context(), &subcategory); err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    subcategory,
+	})
+}
+
+func UpdateSubcategory(c *gin.Context) {
+	var subcategory FoodSubcategoryUpdate
+	if err := c.ShouldBind(&subcategory); err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
+	updateSubcategoryRes(c, func(current FoodSubcategoryUpdate) (FoodSubcategoryUpdate, error) {
+		if subcategory.Category == "" {
+			subcategory.Category = current.Category
+		}
+		return subcategory, nil
+	})
+}
+
+func PatchSubcategory(c *gin.Context) {
+	patch, err := utils.MergePatchBody[FoodSubcategoryUpdate](c)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	updateSubcategoryRes(c, func(current FoodSubcategoryUpdate) (FoodSubcategoryUpdate, error) {
+		return utils.ApplyMergePatch(current, patch)
+	})
+}
+
+func updateSubcategoryRes(c *gin.Context, update func(FoodSubcategoryUpdate) (FoodSubcategoryUpdate, error)) {
+	subcategory, err := updateSubcategory(c.Request.Context(), newFoodSubcategoryFromParams(c), update)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrSubcategoryNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		utils.ErrRes(c, err, statusCode)
+		return
+	}
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered: utils.Negotiate,
 		Data:    subcategory,
@@ -86,21 +139,38 @@ func AddSubcategory(c *gin.Context) {
 }
 
 func DelSubcategory(c *gin.Context) {
-	var (
-		rows int64
-		err  error
-	)
-	if rows, err = delSubcategory(c.Request.Context(), newFoodSubcategoryFromParams(c)); err != nil {
+	opts, err := utils.ParseDeleteOptions(c)
+	if err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
+	result, err := delSubcategory(c.Request.Context(), newFoodSubcategoryFromParams(c), opts)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrSubcategoryNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		utils.ErrRes(c, utils.DeleteErr(err), statusCode)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    result,
+	})
+}
+
+func RestoreSubcategory(c *gin.Context) {
+	result, err := restoreSubcategory(c.Request.Context(), newFoodSubcategoryFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered: utils.Negotiate,
-		Data: utils.WrapperResponse{
-			Msg:  "category rows deleted " + strconv.Itoa(int(rows)),
-			Code: http.StatusOK,
-		},
+		Data:    result,
 	})
 }
 
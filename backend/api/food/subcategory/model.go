@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 
 	c "github.com/MrTimeout/go-home/backend/api/food/category"
+	"gorm.io/gorm"
 )
 
 // FoodSubcategory
@@ -21,24 +22,63 @@ type FoodSubcategory struct {
 	//
 	// required: true
 	// min length: 2
+	// max length: 64
 	// example: Dark Green vegetable
-	Name string `gorm:"column:name;not null;unique" json:"name,omitempty" xml:"Name"`
+	Name string `gorm:"column:name;not null;uniqueIndex:food_subcategories_name_key,where:deleted_at IS NULL" json:"name,omitempty" xml:"Name" binding:"required,min=2,max=64,trimmed,food_name"`
 	// Description represents a little definition of each subcategory
 	//
 	// required: true
 	// min length: 5
+	// max length: 512
 	// example: dark green vegetables as broccoli, collard greens, spinach, romaine, etc.
-	Description    string         `gorm:"column:description;not null" json:"description,omitempty" xml:"Description"`
+	Description    string         `gorm:"column:description;not null" json:"description,omitempty" xml:"Description" binding:"required,min=5,max=512,trimmed"`
 	FoodCategoryID int            `json:"-" xml:"-"`
-	FoodCategory   c.FoodCategory `json:"-" xml:"-"`
+	FoodCategory   c.FoodCategory `json:"-" xml:"-" binding:"-"`
+	// swagger:ignore
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-" xml:"-"`
 }
 
-// OrderByColumnsAllowed return the list of columns allowed to order by.
+// OrderByColumnsAllowed return the list of fields allowed to order by, mapped to their columns.
 func (FoodSubcategory) OrderByColumnsAllowed() map[string]any {
-	return map[string]any{"id": struct{}{}, "name": struct{}{}}
+	return map[string]any{"id": "food_subcategory_id", "name": "name"}
+}
+
+// FilterColumnsAllowed return the list of fields allowed to filter by, mapped to their columns.
+func (FoodSubcategory) FilterColumnsAllowed() map[string]any {
+	return map[string]any{"id": "food_subcategory_id", "name": "name", "description": "description"}
 }
 
 // TableName returns the name of the table that is going to be used to represent the FoodSubcategory struct
 func (FoodSubcategory) TableName() string {
 	return "food_subcategories"
 }
+
+// FoodSubcategoryUpdate
+//
+// It is the representation of a subcategory used to replace or patch it. Changing the
+// category moves the subcategory to another category.
+//
+// swagger:model food-subcategory-update
+type FoodSubcategoryUpdate struct {
+	// swagger:ignore
+	XMLName xml.Name `json:"-" xml:"SubCategory"`
+	// Name represents the name of the subcategory and is unique along the table
+	//
+	// required: true
+	// min length: 2
+	// max length: 64
+	// example: Dark Green vegetable
+	Name string `json:"name" xml:"Name" binding:"required,min=2,max=64,trimmed,food_name"`
+	// Description represents a little definition of each subcategory
+	//
+	// required: true
+	// min length: 5
+	// max length: 512
+	// example: dark green vegetables as broccoli, collard greens, spinach, romaine, etc.
+	Description string `json:"description" xml:"Description" binding:"required,min=5,max=512,trimmed"`
+	// Category is the name of the category of the subcategory. If it is empty, the
+	// subcategory stays in the same category
+	//
+	// example: vegetables
+	Category string `json:"category,omitempty" xml:"Category,omitempty" binding:"omitempty,max=64,trimmed,food_name"`
+}
@@ -2,7 +2,6 @@ package subcategory
 
 import (
 	"context"
-	"errors"
 
 	ca "github.com/MrTimeout/go-home/backend/api/food/category"
 	"github.com/MrTimeout/go-home/backend/api/utils"
@@ -16,32 +15,172 @@ func addSubcategory(ctx context.Context, fc *FoodSubcategory) error {
 		if txx.Error != nil {
 			return txx.Error
 		} else if fc.FoodCategory.ID == 0 {
-			return errors.New("joder bro")
+			return utils.NewProblemError(utils.ParentMissing, ca.ErrCategoryNotFound)
 		}
 
 		return tx.Create(fc).Error
 	})
 }
 
-func delSubcategory(ctx context.Context, fc FoodSubcategory) (rows int64, err error) {
-	tx := WhereSubcategories(SubqueryCategories(config.GetInstance(ctx), fc), fc).Delete(fc)
-	return tx.RowsAffected, tx.Error
+// UpsertSubcategory creates the subcategory fs in the category fs.FoodCategory, found by name, or
+// updates the one with the same name restoring it from the trash if needed. The subcategory is moved
+// if the category changes. It is used to load the catalog in bulk, so it can be run several times.
+// In utils.Insert mode, it fails with utils.ErrAlreadyExists instead of updating.
+func UpsertSubcategory(ctx context.Context, fs FoodSubcategory, mode utils.Mode) (outcome utils.Outcome, err error) {
+	if err = utils.Validate(fs); err != nil {
+		return outcome, err
+	}
+
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		var current FoodSubcategory
+
+		category := ca.FoodCategory{Name: fs.FoodCategory.Name}
+		if txx := tx.Where(&category).Find(&category); txx.Error != nil {
+			return txx.Error
+		} else if category.ID == 0 {
+			return utils.NewProblemError(utils.ParentMissing, ca.ErrCategoryNotFound)
+		}
+
+		fs.FoodCategoryID, fs.FoodCategory = category.ID, category
+
+		if txx := tx.Unscoped().Where("name = ?", fs.Name).Order(utils.ActiveFirst).Find(&current); txx.Error != nil {
+			return txx.Error
+		} else if current.ID == 0 {
+			outcome = utils.Created
+			return tx.Create(&fs).Error
+		} else if mode == utils.Insert {
+			return utils.ErrAlreadyExists
+		} else if current.Description == fs.Description && current.FoodCategoryID == fs.FoodCategoryID && !current.DeletedAt.Valid {
+			outcome = utils.Unchanged
+			return nil
+		}
+
+		outcome = utils.Updated
+		current.Description, current.FoodCategoryID, current.DeletedAt = fs.Description, fs.FoodCategoryID, gorm.DeletedAt{}
+
+		return tx.Unscoped().Model(&current).Select("description", "food_category_id", "deleted_at").Updates(&current).Error
+	})
+
+	return outcome, err
+}
+
+// updateSubcategory replaces the subcategory found by fs with the one returned by update, which
+// receives the current state of the subcategory. The subcategory is moved if the category changes.
+func updateSubcategory(
+	ctx context.Context,
+	fs FoodSubcategory,
+	update func(FoodSubcategoryUpdate) (FoodSubcategoryUpdate, error),
+) (FoodSubcategoryUpdate, error) {
+	var next FoodSubcategoryUpdate
+
+	err := config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			current  FoodSubcategory
+			category = fs.FoodCategory
+			err      error
+		)
+
+		if txx := JoinCategories(WhereSubcategories(tx, fs), fs).Find(&current); txx.Error != nil {
+			return txx.Error
+		} else if current.ID == 0 {
+			return ErrSubcategoryNotFound
+		}
+
+		next, err = update(FoodSubcategoryUpdate{
+			Name:        current.Name,
+			Description: current.Description,
+			Category:    category.Name,
+		})
+		if err != nil {
+			return err
+		} else if err = utils.Validate(next); err != nil {
+			return err
+		}
+
+		if next.Category != category.Name {
+			category = ca.FoodCategory{Name: next.Category}
+			if txx := tx.Where(&category).Find(&category); txx.Error != nil {
+				return txx.Error
+			} else if category.ID == 0 {
+				return utils.NewProblemError(utils.ParentMissing, ca.ErrCategoryNotFound)
+			}
+			current.FoodCategoryID = category.ID
+		}
+
+		current.Name, current.Description = next.Name, next.Description
+
+		return tx.Model(&current).Select("name", "description", "food_category_id").Updates(&current).Error
+	})
+
+	return next, err
+}
+
+// delSubcategory moves the subcategories found by fs to the trash, along with their units and varieties
+// if opts.Cascade is true.
+func delSubcategory(ctx context.Context, fs FoodSubcategory, opts utils.DeleteOptions) (result utils.DeleteResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := subcategoryNodes(tx, fs, false)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return ErrSubcategoryNotFound
+		}
+
+		result, err = utils.DeleteTree(tx, config.GetDrySession(ctx), ca.Hierarchy[1:], roots, opts)
+		return err
+	})
+
+	return result, err
+}
+
+// restoreSubcategory restores the subcategories found by fs from the trash, along with the units and varieties
+// deleted at the same time.
+func restoreSubcategory(ctx context.Context, fs FoodSubcategory) (result utils.RestoreResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := subcategoryNodes(tx, fs, true)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return utils.ErrNotInTrash
+		}
+
+		result, err = utils.RestoreTree(tx, ca.Hierarchy[1:], roots)
+		return err
+	})
+
+	return result, err
+}
+
+// subcategoryNodes returns the subcategories found by fs which are in the trash if deleted is true,
+// or the ones which are not otherwise.
+func subcategoryNodes(tx *gorm.DB, fs FoodSubcategory, deleted bool) ([]utils.Node, error) {
+	var nodes []utils.Node
+
+	tx = JoinCategories(WhereSubcategories(tx.Table(fs.TableName()), fs), fs).
+		Where(utils.TrashCondition(fs.TableName(), deleted)).
+		Select(fs.TableName()+".food_subcategory_id AS id", fs.TableName()+".name", fs.TableName()+".deleted_at").
+		Find(&nodes)
+
+	return nodes, tx.Error
 }
 
 func getSubcategories(ctx context.Context, wrap utils.WrapperRequest[FoodSubcategory]) ([]FoodSubcategory, error) {
 	var result []FoodSubcategory
 
-	tx := ca.WhereCategories(
-		WhereSubcategories(wrap.ToScope(config.GetInstance(ctx)), wrap.Body).
-			Joins("INNER JOIN food_categories USING(food_category_id)"), wrap.Body.FoodCategory).
-		Find(&result)
+	tx := JoinCategories(WhereSubcategories(wrap.ToScope(config.GetInstance(ctx)), wrap.Body), wrap.Body).Find(&result)
 
 	return result, tx.Error
 }
 
+func countSubcategories(ctx context.Context, wrap utils.WrapperRequest[FoodSubcategory]) (total int64, err error) {
+	tx := JoinCategories(WhereSubcategories(wrap.ToFilter(config.GetInstance(ctx).Model(&FoodSubcategory{})), wrap.Body), wrap.Body).
+		Count(&total)
+	return total, tx.Error
+}
+
 func WhereSubcategories(db *gorm.DB, fc FoodSubcategory) *gorm.DB {
 	if fc.ID != 0 {
-		db = db.Where(fc.TableName()+".id = ?", fc.ID)
+		db = db.Where(fc.TableName()+".food_subcategory_id = ?", fc.ID)
 	}
 
 	if fc.Name != "" {
@@ -55,12 +194,15 @@ func WhereSubcategories(db *gorm.DB, fc FoodSubcategory) *gorm.DB {
 	return db
 }
 
+// JoinCategories joins the categories of the subcategories, skipping the ones in the trash.
 func JoinCategories(db *gorm.DB, fs FoodSubcategory) *gorm.DB {
-	return ca.WhereCategories(db.Joins("JOIN "+fs.FoodCategory.TableName()+" USING(food_category_id)"), fs.FoodCategory)
+	db = db.Joins("JOIN " + fs.FoodCategory.TableName() + " USING(food_category_id)").
+		Where(fs.FoodCategory.TableName() + ".deleted_at IS NULL")
+	return ca.WhereCategories(db, fs.FoodCategory)
 }
 
 func SubqueryCategories(db *gorm.DB, fs FoodSubcategory) *gorm.DB {
-	return db.Where(fs.TableName()+".food_category_id IN (?)", ca.SelectWhereCategories(db, fs.FoodCategory, "id"))
+	return db.Where(fs.TableName()+".food_category_id IN (?)", ca.SelectWhereCategories(db, fs.FoodCategory, "food_category_id"))
 }
 
 func SelectWhereSubcategories(db *gorm.DB, fs FoodSubcategory, projection ...string) *gorm.DB {
@@ -0,0 +1,39 @@
+package trash
+
+import (
+	"net/http"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// TrashPath lists the resources of the whole catalog which are in the trash.
+	// /food/trash?type=unit
+	TrashPath = "/trash"
+
+	// TypeParam is the type of the items to list. It can be passed more than once.
+	TypeParam = "type"
+)
+
+func GetTrash(c *gin.Context) {
+	types, err := utils.ParseValues(c, TypeParam, Types())
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	wrap, err := utils.ParseRequest(c, Query{Types: types})
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	items, err := listTrash(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.PageRes(c, utils.NewCountedPage(wrap, items))
+}
@@ -0,0 +1,30 @@
+package trash
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTrashUnknownType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var (
+		router = gin.New()
+		rec    = httptest.NewRecorder()
+		got    utils.Problem
+	)
+
+	router.GET(TrashPath, GetTrash)
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, TrashPath+"?type=unit&type=recipe", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, utils.BadRequest.URI(), got.Type)
+	assert.Contains(t, got.Detail, `got "recipe"`)
+}
@@ -0,0 +1,97 @@
+package trash
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+)
+
+// pathSeparator separates each level of the hierarchy path of an item.
+const pathSeparator = " > "
+
+// Types returns all the types of items, sorted by their level in the hierarchy.
+func Types() []string {
+	result := make([]string, len(ca.Hierarchy))
+	for i, each := range ca.Hierarchy {
+		result[i] = each.Kind
+	}
+
+	return result
+}
+
+// Query is the listing of the trash requested by the client.
+type Query struct {
+	// Types are the types of items to list. All of them are listed if it is empty.
+	Types []string
+}
+
+// OrderByColumnsAllowed returns no columns, items are always ordered by deletion time.
+func (Query) OrderByColumnsAllowed() map[string]any {
+	return map[string]any{}
+}
+
+// Item
+//
+// It is each of the resources of the catalog in the trash, along with its position
+// inside of the hierarchy.
+//
+// swagger:model trash-item
+type Item struct {
+	// swagger:ignore
+	XMLName xml.Name `gorm:"-" json:"-" xml:"Item"`
+	// Type is the kind of resource deleted
+	//
+	// enum: category,subcategory,unit,variety
+	// example: unit
+	Type string `gorm:"column:type" json:"type" xml:"Type"`
+	// Name of the resource deleted
+	//
+	// example: apple
+	Name string `gorm:"column:name" json:"name" xml:"Name"`
+	// Category of the resource, or the resource itself if it is a category
+	//
+	// example: fruit
+	Category string `gorm:"column:category" json:"category" xml:"Category"`
+	// Subcategory of the resource, if any
+	//
+	// example: Whole fruit
+	Subcategory string `gorm:"column:subcategory" json:"subcategory,omitempty" xml:"Subcategory,omitempty"`
+	// Unit of the resource, if any
+	//
+	// example: apple
+	Unit string `gorm:"column:unit" json:"unit,omitempty" xml:"Unit,omitempty"`
+	// Variety of the resource, if any
+	//
+	// example: Granny Smith
+	Variety string `gorm:"column:variety" json:"variety,omitempty" xml:"Variety,omitempty"`
+	// Path is the hierarchy path of the resource
+	//
+	// example: fruit > Whole fruit > apple
+	Path string `gorm:"-" json:"path" xml:"Path"`
+	// DeletedAt is when the resource was moved to the trash
+	//
+	// example: 2022-09-01T10:00:00Z
+	DeletedAt time.Time `gorm:"column:deleted_at" json:"deleted_at" xml:"DeletedAt"`
+	// swagger:ignore
+	Total int64 `gorm:"column:total" json:"-" xml:"-"`
+}
+
+// RowsTotal returns the amount of items in the trash without pagination.
+func (i Item) RowsTotal() int64 {
+	return i.Total
+}
+
+// path returns the hierarchy path of the item: category > subcategory > unit > variety
+func (i Item) path() string {
+	var levels = make([]string, 0, 4)
+
+	for _, each := range []string{i.Category, i.Subcategory, i.Unit, i.Variety} {
+		if each != "" {
+			levels = append(levels, each)
+		}
+	}
+
+	return strings.Join(levels, pathSeparator)
+}
@@ -0,0 +1,78 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"gorm.io/gorm"
+)
+
+// branch returns the query which lists the items in the trash of the level i of the hierarchy,
+// joining all its parents to know the hierarchy path.
+func branch(i int) string {
+	var (
+		levels = []string{"''", "''", "''", "''"}
+		from   = fmt.Sprintf("%s AS l%d", ca.Hierarchy[i].Table, i)
+	)
+
+	for j := i; j >= 0; j-- {
+		levels[j] = fmt.Sprintf("l%d.name", j)
+
+		if j < i {
+			from += fmt.Sprintf(" JOIN %s AS l%d USING(%s)", ca.Hierarchy[j].Table, j, ca.Hierarchy[j].Key)
+		}
+	}
+
+	return fmt.Sprintf(
+		`SELECT '%s' AS type, l%[2]d.name AS name, %s AS category, %s AS subcategory, %s AS unit, %s AS variety, `+
+			`l%[2]d.deleted_at AS deleted_at FROM %[7]s WHERE l%[2]d.deleted_at IS NOT NULL`,
+		ca.Hierarchy[i].Kind, i, levels[0], levels[1], levels[2], levels[3], from,
+	)
+}
+
+func listTrash(ctx context.Context, wrap utils.WrapperRequest[Query]) ([]Item, error) {
+	var (
+		result  []Item
+		queries = make([]string, 0, len(ca.Hierarchy))
+	)
+
+	for i, each := range ca.Hierarchy {
+		if len(wrap.Body.Types) == 0 || utils.Contains(wrap.Body.Types, each.Kind) {
+			queries = append(queries, branch(i))
+		}
+	}
+
+	if len(queries) == 0 {
+		return result, nil
+	}
+
+	tx := config.GetInstance(ctx).Raw(
+		"SELECT items.*, count(*) OVER () AS total FROM ("+strings.Join(queries, " UNION ALL ")+") AS items "+
+			"ORDER BY items.deleted_at DESC, items.type, items.name LIMIT @limit OFFSET @skip",
+		map[string]any{"limit": wrap.Limit, "skip": wrap.Skip},
+	).Scan(&result)
+
+	for i := range result {
+		result[i].Path = result[i].path()
+	}
+
+	return result, tx.Error
+}
+
+// Purge hard deletes the resources of the whole catalog which have been in the trash for
+// longer than retention. It returns the amount of resources purged of each type.
+func Purge(ctx context.Context, retention time.Duration) (rows map[string]int64, err error) {
+	before := time.Now().Add(-retention)
+
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		rows, err = utils.PurgeTree(tx, ca.Hierarchy, before)
+		return err
+	})
+
+	return rows, err
+}
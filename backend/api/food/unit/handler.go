@@ -3,7 +3,6 @@ package unit
 import (
 	"errors"
 	"net/http"
-	"strconv"
 
 	ca "github.com/MrTimeout/go-home/backend/api/food/category"
 	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
@@ -21,6 +20,9 @@ const (
 	// UnitBySubcategoryPath returns the unit by subcategory.
 	// /food/subcategories/:subcategory-name/units/:unit-name
 	UnitBySubcategoryPath = UnitsBySubcategoriesPath + "/:" + UnitNameParam
+	// UnitRestoreBySubcategoryPath restores the unit from the trash.
+	// /food/subcategories/:subcategory-name/units/:unit-name/restore
+	UnitRestoreBySubcategoryPath = UnitBySubcategoryPath + utils.RestorePathName
 
 	// UnitsByCategoriesPath retrieves all the units inside a category.
 	// /food/categories/:category-name/units
@@ -37,28 +39,40 @@ const (
 var ErrUnitsNotFound = errors.New("units not found")
 
 func GetUnitsBySubcategory(c *gin.Context) {
-	units, err := getUnits(c.Request.Context(), utils.ParseRequest(c, newFoodUnitFromParams(c)))
-	if err != nil || len(units) == 0 {
-		if err == nil {
-			err = ErrUnitsNotFound
-		}
-		utils.ErrRes(c, err, http.StatusNotFound)
+	wrap, err := utils.ParseRequest(c, newFoodUnitFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
-	c.Negotiate(http.StatusOK, gin.Negotiate{
-		Offered: utils.Negotiate,
-		Data:    units,
-	})
+	units, err := getUnits(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	total, err := countUnits(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.PageRes(c, wrap.NewPage(units, total))
 }
 
 func GetUnitBySubcategory(c *gin.Context) {
-	subcategory, err := getUnits(c.Request.Context(), utils.ParseRequest(c, newFoodUnitFromParams(c)))
-	if err != nil || len(subcategory) == 0 {
-		if err == nil {
-			err = ErrUnitsNotFound
-		}
-		utils.ErrRes(c, err, http.StatusNotFound)
+	wrap, err := utils.ParseRequest(c, newFoodUnitFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	subcategory, err := getUnits(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	} else if len(subcategory) == 0 {
+		utils.ErrRes(c, ErrUnitsNotFound, http.StatusNotFound)
 		return
 	}
 
@@ -69,28 +83,40 @@ func GetUnitBySubcategory(c *gin.Context) {
 }
 
 func GetUnitsByCategory(c *gin.Context) {
-	units, err := getUnits(c.Request.Context(), utils.ParseRequest(c, newFoodUnitFromParams(c)))
-	if err != nil || len(units) == 0 {
-		if err == nil {
-			err = ErrUnitsNotFound
-		}
-		utils.ErrRes(c, err, http.StatusNotFound)
+	wrap, err := utils.ParseRequest(c, newFoodUnitFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
-	c.Negotiate(http.StatusOK, gin.Negotiate{
-		Offered: utils.Negotiate,
-		Data:    units,
-	})
+	units, err := getUnits(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	total, err := countUnits(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.PageRes(c, wrap.NewPage(units, total))
 }
 
 func GetUnitByCategory(c *gin.Context) {
-	subcategory, err := getUnits(c.Request.Context(), utils.ParseRequest(c, newFoodUnitFromParams(c)))
-	if err != nil || len(subcategory) == 0 {
-		if err == nil {
-			err = ErrUnitsNotFound
-		}
-		utils.ErrRes(c, err, http.StatusNotFound)
+	wrap, err := utils.ParseRequest(c, newFoodUnitFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	subcategory, err := getUnits(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	} else if len(subcategory) == 0 {
+		utils.ErrRes(c, ErrUnitsNotFound, http.StatusNotFound)
 		return
 	}
 
@@ -102,7 +128,7 @@ func GetUnitByCategory(c *gin.Context) {
 
 func AddUnit(c *gin.Context) {
 	var unit FoodUnit
-	if err := c.Bind(&unit); err != nil {
+	if err := c.ShouldBind(&unit); err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
@@ -110,10 +136,54 @@ func AddUnit(c *gin.Context) {
 	unit.FoodSubcategory.Name = c.Param(sca.SubcategoryNameParam)
 
 	if err := addUnit(c.Request.Context(), &unit); err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    unit,
+	})
+}
+
+func UpdateUnit(c *gin.Context) {
+	var unit FoodUnitUpdate
+	if err := c.ShouldBind(&unit); err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
+	updateUnitRes(c, func(current FoodUnitUpdate) (FoodUnitUpdate, error) {
+		if unit.Subcategory == "" {
+			unit.Subcategory = current.Subcategory
+		}
+		return unit, nil
+	})
+}
+
+func PatchUnit(c *gin.Context) {
+	patch, err := utils.MergePatchBody[FoodUnitUpdate](c)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	updateUnitRes(c, func(current FoodUnitUpdate) (FoodUnitUpdate, error) {
+		return utils.ApplyMergePatch(current, patch)
+	})
+}
+
+func updateUnitRes(c *gin.Context, update func(FoodUnitUpdate) (FoodUnitUpdate, error)) {
+	unit, err := updateUnit(c.Request.Context(), newFoodUnitFromParams(c), update)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrUnitsNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		utils.ErrRes(c, err, statusCode)
+		return
+	}
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered: utils.Negotiate,
 		Data:    unit,
@@ -121,21 +191,38 @@ func AddUnit(c *gin.Context) {
 }
 
 func DelUnit(c *gin.Context) {
-	var (
-		rows int64
-		err  error
-	)
-	if rows, err = delUnit(c.Request.Context(), newFoodUnitFromParams(c)); err != nil {
+	opts, err := utils.ParseDeleteOptions(c)
+	if err != nil {
 		utils.ErrRes(c, err, http.StatusBadRequest)
 		return
 	}
 
+	result, err := delUnit(c.Request.Context(), newFoodUnitFromParams(c), opts)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrUnitsNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		utils.ErrRes(c, utils.DeleteErr(err), statusCode)
+		return
+	}
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered: utils.Negotiate,
-		Data: utils.WrapperResponse{
-			Msg:  "category rows deleted " + strconv.Itoa(int(rows)),
-			Code: http.StatusOK,
-		},
+		Data:    result,
+	})
+}
+
+func RestoreUnit(c *gin.Context) {
+	result, err := restoreUnit(c.Request.Context(), newFoodUnitFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    result,
 	})
 }
 
@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGetUnitsEmpty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=home"), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(config.WithTransaction(c.Request.Context(), db))
+	})
+	router.GET(UnitsBySubcategoriesPath, GetUnitsBySubcategory)
+	router.GET(UnitsByCategoriesPath, GetUnitsByCategory)
+
+	for _, each := range []string{"/subcategories/citrus/units?skip=100", "/categories/fruit/units?filter=name:eq:none"} {
+		t.Run(each, func(t *testing.T) {
+			var (
+				rec = httptest.NewRecorder()
+				got utils.Page[FoodUnit]
+			)
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, each, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code, "an empty page is not a missing resource")
+			assert.Equal(t, "0", rec.Header().Get(utils.TotalCountHeader))
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Empty(t, got.Items)
+			assert.False(t, got.HasMore)
+		})
+	}
+}
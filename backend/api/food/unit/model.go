@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 
 	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
+	"gorm.io/gorm"
 )
 
 // FoodUnit
@@ -20,24 +21,63 @@ type FoodUnit struct {
 	//
 	// required: true
 	// min length: 2
+	// max length: 64
 	// example: banana
-	Name string `gorm:"column:name;not null;unique" json:"name" xml:"Name"`
+	Name string `gorm:"column:name;not null;uniqueIndex:food_units_name_key,where:deleted_at IS NULL" json:"name" xml:"Name" binding:"required,min=2,max=64,trimmed,food_name"`
 	// Description of the food unit. It can be as large as you want
 	//
 	// required: true
 	// min length: 5
+	// max length: 2048
 	// example: a long curved fruit which grows in clusters and has soft pulpy flesh and yellow skin when ripe.
-	Description       string              `gorm:"column:description;not null" json:"description" xml:"Description"`
+	Description       string              `gorm:"column:description;not null" json:"description" xml:"Description" binding:"required,min=5,max=2048,trimmed"`
 	FoodSubcategoryID int                 `json:"-" xml:"-"`
-	FoodSubcategory   sca.FoodSubcategory `json:"-" xml:"-"`
+	FoodSubcategory   sca.FoodSubcategory `json:"-" xml:"-" binding:"-"`
+	// swagger:ignore
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-" xml:"-"`
 }
 
-// OrderByColumnsAllowed return the list of columns allowed to order by.
+// OrderByColumnsAllowed return the list of fields allowed to order by, mapped to their columns.
 func (FoodUnit) OrderByColumnsAllowed() map[string]any {
-	return map[string]any{"id": struct{}{}, "name": struct{}{}}
+	return map[string]any{"id": "food_unit_id", "name": "name"}
+}
+
+// FilterColumnsAllowed return the list of fields allowed to filter by, mapped to their columns.
+func (FoodUnit) FilterColumnsAllowed() map[string]any {
+	return map[string]any{"id": "food_unit_id", "name": "name", "description": "description"}
 }
 
 // TableName returns the name of the table that is going to be used to represent the FoodSubcategory struct
 func (FoodUnit) TableName() string {
 	return "food_units"
 }
+
+// FoodUnitUpdate
+//
+// It is the representation of a unit used to replace or patch it. Changing the
+// subcategory moves the unit to another subcategory.
+//
+// swagger:model food-unit-update
+type FoodUnitUpdate struct {
+	// swagger:ignore
+	XMLName xml.Name `json:"-" xml:"FoodUnit"`
+	// Name that uniquely identifies a piece of food
+	//
+	// required: true
+	// min length: 2
+	// max length: 64
+	// example: banana
+	Name string `json:"name" xml:"Name" binding:"required,min=2,max=64,trimmed,food_name"`
+	// Description of the food unit. It can be as large as you want
+	//
+	// required: true
+	// min length: 5
+	// max length: 2048
+	// example: a long curved fruit which grows in clusters and has soft pulpy flesh and yellow skin when ripe.
+	Description string `json:"description" xml:"Description" binding:"required,min=5,max=2048,trimmed"`
+	// Subcategory is the name of the subcategory of the unit. If it is empty, the
+	// unit stays in the same subcategory
+	//
+	// example: Whole fruit
+	Subcategory string `json:"subcategory,omitempty" xml:"Subcategory,omitempty" binding:"omitempty,max=64,trimmed,food_name"`
+}
@@ -2,8 +2,8 @@ package unit
 
 import (
 	"context"
-	"errors"
 
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
 	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
 	"github.com/MrTimeout/go-home/backend/api/utils"
 	"github.com/MrTimeout/go-home/backend/internals/config"
@@ -16,35 +16,167 @@ func addUnit(ctx context.Context, fu *FoodUnit) error {
 		if txx.Error != nil {
 			return txx.Error
 		} else if fu.FoodSubcategory.ID == 0 {
-			return errors.New("joder bro")
+			return utils.NewProblemError(utils.ParentMissing, sca.ErrSubcategoryNotFound)
 		}
 
 		return tx.Create(fu).Error
 	})
 }
 
-func delUnit(ctx context.Context, fu FoodUnit) (rows int64, err error) {
-	tx := WhereUnit(SubQueryUnit(config.GetInstance(ctx), fu), fu).Delete(&fu)
-	return tx.RowsAffected, tx.Error
+// UpsertUnit creates the unit fu in the subcategory fu.FoodSubcategory, found by name, or updates
+// the one with the same name restoring it from the trash if needed. The unit is moved if the
+// subcategory changes. It is used to load the catalog in bulk, so it can be run several times.
+// In utils.Insert mode, it fails with utils.ErrAlreadyExists instead of updating.
+func UpsertUnit(ctx context.Context, fu FoodUnit, mode utils.Mode) (outcome utils.Outcome, err error) {
+	if err = utils.Validate(fu); err != nil {
+		return outcome, err
+	}
+
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		var current FoodUnit
+
+		subcategory := sca.FoodSubcategory{Name: fu.FoodSubcategory.Name}
+		if txx := sca.JoinCategories(sca.WhereSubcategories(tx, subcategory), subcategory).Find(&subcategory); txx.Error != nil {
+			return txx.Error
+		} else if subcategory.ID == 0 {
+			return utils.NewProblemError(utils.ParentMissing, sca.ErrSubcategoryNotFound)
+		}
+
+		fu.FoodSubcategoryID, fu.FoodSubcategory = subcategory.ID, subcategory
+
+		if txx := tx.Unscoped().Where("name = ?", fu.Name).Order(utils.ActiveFirst).Find(&current); txx.Error != nil {
+			return txx.Error
+		} else if current.ID == 0 {
+			outcome = utils.Created
+			return tx.Create(&fu).Error
+		} else if mode == utils.Insert {
+			return utils.ErrAlreadyExists
+		} else if current.Description == fu.Description && current.FoodSubcategoryID == fu.FoodSubcategoryID && !current.DeletedAt.Valid {
+			outcome = utils.Unchanged
+			return nil
+		}
+
+		outcome = utils.Updated
+		current.Description, current.FoodSubcategoryID, current.DeletedAt = fu.Description, fu.FoodSubcategoryID, gorm.DeletedAt{}
+
+		return tx.Unscoped().Model(&current).Select("description", "food_subcategory_id", "deleted_at").Updates(&current).Error
+	})
+
+	return outcome, err
+}
+
+// updateUnit replaces the unit found by fu with the one returned by update, which receives
+// the current state of the unit. The unit is moved if the subcategory changes.
+func updateUnit(ctx context.Context, fu FoodUnit, update func(FoodUnitUpdate) (FoodUnitUpdate, error)) (FoodUnitUpdate, error) {
+	var next FoodUnitUpdate
+
+	err := config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			current     FoodUnit
+			subcategory = fu.FoodSubcategory
+			err         error
+		)
+
+		if txx := JoinParents(WhereUnit(tx, fu), fu).Find(&current); txx.Error != nil {
+			return txx.Error
+		} else if current.ID == 0 {
+			return ErrUnitsNotFound
+		}
+
+		next, err = update(FoodUnitUpdate{
+			Name:        current.Name,
+			Description: current.Description,
+			Subcategory: subcategory.Name,
+		})
+		if err != nil {
+			return err
+		} else if err = utils.Validate(next); err != nil {
+			return err
+		}
+
+		if next.Subcategory != subcategory.Name {
+			subcategory = sca.FoodSubcategory{Name: next.Subcategory}
+			if txx := sca.JoinCategories(sca.WhereSubcategories(tx, subcategory), subcategory).Find(&subcategory); txx.Error != nil {
+				return txx.Error
+			} else if subcategory.ID == 0 {
+				return utils.NewProblemError(utils.ParentMissing, sca.ErrSubcategoryNotFound)
+			}
+			current.FoodSubcategoryID = subcategory.ID
+		}
+
+		current.Name, current.Description = next.Name, next.Description
+
+		return tx.Model(&current).Select("name", "description", "food_subcategory_id").Updates(&current).Error
+	})
+
+	return next, err
+}
+
+// delUnit moves the units found by fu to the trash, along with their varieties
+// if opts.Cascade is true.
+func delUnit(ctx context.Context, fu FoodUnit, opts utils.DeleteOptions) (result utils.DeleteResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := unitNodes(tx, fu, false)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return ErrUnitsNotFound
+		}
+
+		result, err = utils.DeleteTree(tx, config.GetDrySession(ctx), ca.Hierarchy[2:], roots, opts)
+		return err
+	})
+
+	return result, err
+}
+
+// restoreUnit restores the units found by fu from the trash, along with the varieties
+// deleted at the same time.
+func restoreUnit(ctx context.Context, fu FoodUnit) (result utils.RestoreResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := unitNodes(tx, fu, true)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return utils.ErrNotInTrash
+		}
+
+		result, err = utils.RestoreTree(tx, ca.Hierarchy[2:], roots)
+		return err
+	})
+
+	return result, err
+}
+
+// unitNodes returns the units found by fu which are in the trash if deleted is true,
+// or the ones which are not otherwise.
+func unitNodes(tx *gorm.DB, fu FoodUnit, deleted bool) ([]utils.Node, error) {
+	var nodes []utils.Node
+
+	tx = JoinParents(WhereUnit(tx.Table(fu.TableName()), fu), fu).
+		Where(utils.TrashCondition(fu.TableName(), deleted)).
+		Select(fu.TableName()+".food_unit_id AS id", fu.TableName()+".name", fu.TableName()+".deleted_at").
+		Find(&nodes)
+
+	return nodes, tx.Error
 }
 
 func getUnits(ctx context.Context, wrap utils.WrapperRequest[FoodUnit]) ([]FoodUnit, error) {
 	var result []FoodUnit
 
-	tx := WhereUnit(wrap.ToScope(config.GetInstance(ctx)), wrap.Body)
-
-	if wrap.Body.FoodSubcategory.FoodCategory.Name != "" {
-		tx = JoinCategories(tx, wrap.Body).Find(&result)
-	} else {
-		tx = JoinSubcategories(tx, wrap.Body).Find(&result)
-	}
+	tx := JoinParents(WhereUnit(wrap.ToScope(config.GetInstance(ctx)), wrap.Body), wrap.Body).Find(&result)
 
 	return result, tx.Error
 }
 
+func countUnits(ctx context.Context, wrap utils.WrapperRequest[FoodUnit]) (total int64, err error) {
+	tx := JoinParents(WhereUnit(wrap.ToFilter(config.GetInstance(ctx).Model(&FoodUnit{})), wrap.Body), wrap.Body).Count(&total)
+	return total, tx.Error
+}
+
 func WhereUnit(db *gorm.DB, fu FoodUnit) *gorm.DB {
 	if fu.ID != 0 {
-		db = db.Where(fu.TableName()+".id = ?", fu.ID)
+		db = db.Where(fu.TableName()+".food_unit_id = ?", fu.ID)
 	}
 
 	if fu.Name != "" {
@@ -58,8 +190,10 @@ func WhereUnit(db *gorm.DB, fu FoodUnit) *gorm.DB {
 	return db
 }
 
+// JoinSubcategories joins the subcategories of the units, skipping the ones in the trash.
 func JoinSubcategories(db *gorm.DB, fu FoodUnit) *gorm.DB {
-	db = db.Joins("JOIN " + fu.FoodSubcategory.TableName() + " USING(food_subcategory_id)")
+	db = db.Joins("JOIN " + fu.FoodSubcategory.TableName() + " USING(food_subcategory_id)").
+		Where(fu.FoodSubcategory.TableName() + ".deleted_at IS NULL")
 	return sca.WhereSubcategories(db, fu.FoodSubcategory)
 }
 
@@ -68,5 +202,15 @@ func JoinCategories(db *gorm.DB, fu FoodUnit) *gorm.DB {
 }
 
 func SubQueryUnit(db *gorm.DB, fu FoodUnit) *gorm.DB {
-	return db.Where(fu.TableName()+".food_subcategory_id IN (?)", sca.SelectWhereSubcategories(db, fu.FoodSubcategory, "id"))
+	return db.Where(fu.TableName()+".food_subcategory_id IN (?)", sca.SelectWhereSubcategories(db, fu.FoodSubcategory, "food_subcategory_id"))
+}
+
+// JoinParents joins the subcategories of the unit and, only if the category name is present,
+// the categories too.
+func JoinParents(db *gorm.DB, fu FoodUnit) *gorm.DB {
+	if fu.FoodSubcategory.FoodCategory.Name != "" {
+		return JoinCategories(db, fu)
+	}
+
+	return JoinSubcategories(db, fu)
 }
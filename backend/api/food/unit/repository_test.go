@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryPool lets DryRun sessions open transactions without reaching a database.
+type dryPool struct{}
+
+func (*dryPool) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, nil }
+func (*dryPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+func (*dryPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+func (*dryPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+func (p *dryPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+func (*dryPool) Commit() error   { return nil }
+func (*dryPool) Rollback() error { return nil }
+
+func TestUpdateUnitTrashedCategory(t *testing.T) {
+	require.NoError(t, utils.RegisterValidations())
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryPool{}}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+
+	var queries []string
+	// Only the unit is found: the subcategory lookup behaves as if its category were in the trash.
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:query", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+		if tx.Statement.Table == (FoodUnit{}).TableName() && tx.Statement.ReflectValue.Kind() == reflect.Struct {
+			tx.Statement.ReflectValue.FieldByName("ID").SetInt(1)
+		}
+	}))
+
+	ctx := config.WithTransaction(context.Background(), db)
+	fu := FoodUnit{Name: "banana", FoodSubcategory: sca.FoodSubcategory{Name: "tropical"}}
+
+	_, err = updateUnit(ctx, fu, func(FoodUnitUpdate) (FoodUnitUpdate, error) {
+		return FoodUnitUpdate{Name: "banana", Description: "yellow and curved", Subcategory: "citrus"}, nil
+	})
+
+	var problem *utils.ProblemError
+	require.ErrorAs(t, err, &problem)
+	assert.Equal(t, utils.ParentMissing, problem.Type)
+	require.Len(t, queries, 2)
+	assert.True(t, strings.Contains(queries[1], "food_categories.deleted_at IS NULL"), queries[1])
+}
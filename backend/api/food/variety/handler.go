@@ -0,0 +1,158 @@
+package variety
+
+import (
+	"errors"
+	"net/http"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
+	u "github.com/MrTimeout/go-home/backend/api/food/unit"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// VarietyPathName is the variety path name
+	VarietyPathName = "/varieties"
+
+	// VarietiesBySubcategoryPath retrieves all the varieties of a unit inside a subcategory.
+	// /food/subcategories/:subcategory-name/units/:unit-name/varieties
+	VarietiesBySubcategoryPath = u.UnitBySubcategoryPath + VarietyPathName
+	// VarietyBySubcategoryPath returns the variety of a unit inside a subcategory.
+	// /food/subcategories/:subcategory-name/units/:unit-name/varieties/:variety-name
+	VarietyBySubcategoryPath = VarietiesBySubcategoryPath + "/:" + VarietyNameParam
+	// VarietyRestoreBySubcategoryPath restores the variety of a unit inside a subcategory from the trash.
+	// /food/subcategories/:subcategory-name/units/:unit-name/varieties/:variety-name/restore
+	VarietyRestoreBySubcategoryPath = VarietyBySubcategoryPath + utils.RestorePathName
+
+	// VarietiesByCategoryPath retrieves all the varieties of a unit inside a category.
+	// /food/categories/:category-name/units/:unit-name/varieties
+	VarietiesByCategoryPath = u.UnitByCategoriesPath + VarietyPathName
+	// VarietyByCategoryPath returns the variety of a unit inside a category.
+	// /food/categories/:category-name/units/:unit-name/varieties/:variety-name
+	VarietyByCategoryPath = VarietiesByCategoryPath + "/:" + VarietyNameParam
+	// VarietyRestoreByCategoryPath restores the variety of a unit inside a category from the trash.
+	// /food/categories/:category-name/units/:unit-name/varieties/:variety-name/restore
+	VarietyRestoreByCategoryPath = VarietyByCategoryPath + utils.RestorePathName
+
+	// VarietyNameParam is the variety name param
+	VarietyNameParam = "variety-name"
+)
+
+// ErrVarietiesNotFound is returned when no varieties were found.
+var ErrVarietiesNotFound = errors.New("varieties not found")
+
+func GetVarieties(c *gin.Context) {
+	wrap, err := utils.ParseRequest(c, newFoodUnitVarietyFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	varieties, err := getVarieties(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	total, err := countVarieties(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.PageRes(c, wrap.NewPage(varieties, total))
+}
+
+func GetVarietyByName(c *gin.Context) {
+	wrap, err := utils.ParseRequest(c, newFoodUnitVarietyFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	variety, err := getVarieties(c.Request.Context(), wrap)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	} else if len(variety) == 0 {
+		utils.ErrRes(c, ErrVarietiesNotFound, http.StatusNotFound)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    variety,
+	})
+}
+
+func AddVariety(c *gin.Context) {
+	var variety FoodUnitVariety
+	if err := c.ShouldBind(&variety); err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	variety.FoodUnit = newFoodUnitVarietyFromParams(c).FoodUnit
+
+	if err := addVariety(c.Request.Context(), &variety); err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    variety,
+	})
+}
+
+func DelVariety(c *gin.Context) {
+	opts, err := utils.ParseDeleteOptions(c)
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusBadRequest)
+		return
+	}
+
+	result, err := delVariety(c.Request.Context(), newFoodUnitVarietyFromParams(c), opts)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, ErrVarietiesNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		utils.ErrRes(c, utils.DeleteErr(err), statusCode)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    result,
+	})
+}
+
+func RestoreVariety(c *gin.Context) {
+	result, err := restoreVariety(c.Request.Context(), newFoodUnitVarietyFromParams(c))
+	if err != nil {
+		utils.ErrRes(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    result,
+	})
+}
+
+func newFoodUnitVarietyFromParams(pParser utils.ParamParser) FoodUnitVariety {
+	return FoodUnitVariety{
+		Name: pParser.Param(VarietyNameParam),
+		FoodUnit: u.FoodUnit{
+			Name: pParser.Param(u.UnitNameParam),
+			FoodSubcategory: sca.FoodSubcategory{
+				Name: pParser.Param(sca.SubcategoryNameParam),
+				FoodCategory: ca.FoodCategory{
+					Name: pParser.Param(ca.CategoryNameParam),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,222 @@
+package variety
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	u "github.com/MrTimeout/go-home/backend/api/food/unit"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryPool lets DryRun sessions open transactions without reaching a database.
+type dryPool struct{}
+
+func (*dryPool) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, nil }
+func (*dryPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+func (*dryPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+func (*dryPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+func (p *dryPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+func (*dryPool) Commit() error   { return nil }
+func (*dryPool) Rollback() error { return nil }
+
+// newDryDB returns a DryRun database whose queries and updates on the tables found return or
+// affect a single row, named after the variety of the tests. Any other table has no rows.
+func newDryDB(t *testing.T, found ...string) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryPool{}}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:query", func(tx *gorm.DB) {
+		if utils.Contains(found, tx.Statement.Table) {
+			fillRow(tx.Statement.ReflectValue)
+			tx.RowsAffected = 1
+		}
+	}))
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:raw", func(tx *gorm.DB) {
+		for _, table := range found {
+			if strings.HasPrefix(tx.Statement.SQL.String(), "UPDATE "+table+" ") {
+				tx.RowsAffected = 1
+			}
+		}
+	}))
+
+	return db
+}
+
+func fillRow(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Slice:
+		v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+		fillRow(v.Index(v.Len() - 1))
+	case reflect.Struct:
+		if id := v.FieldByName("ID"); id.IsValid() {
+			id.SetInt(1)
+		}
+		if name := v.FieldByName("Name"); name.IsValid() && name.String() == "" {
+			name.SetString("granny smith")
+		}
+	case reflect.Int64:
+		v.SetInt(1)
+	}
+}
+
+func newRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(config.WithTransaction(c.Request.Context(), db))
+	})
+	router.GET(VarietiesBySubcategoryPath, GetVarieties)
+	router.POST(VarietiesBySubcategoryPath, AddVariety)
+	router.GET(VarietyBySubcategoryPath, GetVarietyByName)
+	router.DELETE(VarietyBySubcategoryPath, DelVariety)
+
+	return router
+}
+
+func TestGetVarieties(t *testing.T) {
+	var (
+		rec = httptest.NewRecorder()
+		got utils.Page[FoodUnitVariety]
+	)
+
+	newRouter(newDryDB(t, FoodUnitVariety{}.TableName())).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/subcategories/apples/units/apple/varieties", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(utils.TotalCountHeader))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, "granny smith", got.Items[0].Name)
+}
+
+func TestGetVarietyByName(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		found       []string
+		wantStatus  int
+	}{
+		{description: "variety found", found: []string{FoodUnitVariety{}.TableName()}, wantStatus: http.StatusOK},
+		{description: "missing unit", wantStatus: http.StatusNotFound},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			newRouter(newDryDB(t, each.found...)).
+				ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/subcategories/apples/units/apple/varieties/granny-smith", nil))
+
+			assert.Equal(t, each.wantStatus, rec.Code)
+			if each.wantStatus != http.StatusOK {
+				var got utils.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, utils.NotFound.URI(), got.Type)
+				return
+			}
+
+			var got []FoodUnitVariety
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			require.Len(t, got, 1)
+			assert.Equal(t, "granny smith", got[0].Name)
+		})
+	}
+}
+
+func TestAddVariety(t *testing.T) {
+	require.NoError(t, utils.RegisterValidations())
+
+	valid := `{"name":"granny smith","description":"known for its green flesh","img":"https://example.com/granny.png"}`
+
+	for _, each := range []struct {
+		description, body string
+		found             []string
+		wantStatus        int
+		wantType          utils.ProblemType
+	}{
+		{description: "unit found", body: valid, found: []string{u.FoodUnit{}.TableName()}, wantStatus: http.StatusOK},
+		{description: "missing unit", body: valid, wantStatus: http.StatusUnprocessableEntity, wantType: utils.ParentMissing},
+		{description: "invalid body", body: `{"name":"x"}`, found: []string{u.FoodUnit{}.TableName()}, wantStatus: http.StatusUnprocessableEntity, wantType: utils.ValidationFailed},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				rec = httptest.NewRecorder()
+				req = httptest.NewRequest(http.MethodPost, "/subcategories/apples/units/apple/varieties", strings.NewReader(each.body))
+			)
+
+			req.Header.Set("Content-Type", gin.MIMEJSON)
+			newRouter(newDryDB(t, each.found...)).ServeHTTP(rec, req)
+
+			assert.Equal(t, each.wantStatus, rec.Code)
+			if each.wantStatus == http.StatusOK {
+				var got FoodUnitVariety
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, "granny smith", got.Name)
+				return
+			}
+
+			var got utils.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, each.wantType.URI(), got.Type)
+		})
+	}
+}
+
+func TestDelVariety(t *testing.T) {
+	for _, each := range []struct {
+		description, query string
+		found              []string
+		wantStatus         int
+		wantSQL            bool
+	}{
+		{description: "variety deleted", found: []string{FoodUnitVariety{}.TableName()}, wantStatus: http.StatusOK},
+		{description: "dry run", query: "?dry_run=true", found: []string{FoodUnitVariety{}.TableName()}, wantStatus: http.StatusOK, wantSQL: true},
+		{description: "missing variety", wantStatus: http.StatusNotFound},
+		{description: "invalid options", query: "?cascade=maybe", wantStatus: http.StatusBadRequest},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			newRouter(newDryDB(t, each.found...)).
+				ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/subcategories/apples/units/apple/varieties/granny-smith"+each.query, nil))
+
+			assert.Equal(t, each.wantStatus, rec.Code)
+			if each.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got utils.DeleteResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, int64(1), got.Rows)
+			require.Len(t, got.Nodes, 1)
+			assert.Equal(t, "granny smith", got.Nodes[0].Name)
+			if each.wantSQL {
+				require.Len(t, got.SQL, 1)
+				assert.Contains(t, got.SQL[0], "UPDATE food_unit_varieties SET deleted_at")
+			} else {
+				assert.Empty(t, got.SQL)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package variety
+
+import (
+	"encoding/xml"
+
+	u "github.com/MrTimeout/go-home/backend/api/food/unit"
+	"gorm.io/gorm"
+)
+
+// FoodUnitVariety
+//
+// It is each of the varieties that a food unit can have, like the different types of apples.
+//
+// swagger:model food-unit-variety
+type FoodUnitVariety struct {
+	// swagger:ignore
+	XMLName xml.Name `gorm:"-" json:"-" xml:"FoodUnitVariety"`
+	// swagger:ignore
+	ID int `gorm:"column:food_unit_variety_id;primaryKey" json:"-" xml:"-"`
+	// Name that uniquely identifies a variety of a food unit
+	//
+	// required: true
+	// min length: 2
+	// max length: 64
+	// example: Granny Smith
+	Name string `gorm:"column:name;not null;uniqueIndex:food_unit_varieties_name_key,where:deleted_at IS NULL" json:"name" xml:"Name" binding:"required,min=2,max=64,trimmed,food_name"`
+	// Description of the variety. It can be as large as you want
+	//
+	// required: true
+	// min length: 5
+	// max length: 2048
+	// example: known for their distinctive green flesh and their very tart flavor.
+	Description string `gorm:"column:description;not null" json:"description" xml:"Description" binding:"required,min=5,max=2048,trimmed"`
+	// Img is the URL of an image of the variety
+	//
+	// required: true
+	// max length: 2048
+	// example: https://usapple.org/wp-content/uploads/2019/10/apple-granny-smith.png
+	Img        string     `gorm:"column:img;not null" json:"img" xml:"Img" binding:"required,max=2048,img_url"`
+	FoodUnitID int        `json:"-" xml:"-"`
+	FoodUnit   u.FoodUnit `json:"-" xml:"-" binding:"-"`
+	// swagger:ignore
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-" xml:"-"`
+}
+
+// OrderByColumnsAllowed return the list of fields allowed to order by, mapped to their columns.
+func (FoodUnitVariety) OrderByColumnsAllowed() map[string]any {
+	return map[string]any{"id": "food_unit_variety_id", "name": "name"}
+}
+
+// FilterColumnsAllowed return the list of fields allowed to filter by, mapped to their columns.
+func (FoodUnitVariety) FilterColumnsAllowed() map[string]any {
+	return map[string]any{"id": "food_unit_variety_id", "name": "name", "description": "description"}
+}
+
+// TableName returns the name of the table that is going to be used to represent the FoodUnitVariety struct
+func (FoodUnitVariety) TableName() string {
+	return "food_unit_varieties"
+}
@@ -0,0 +1,155 @@
+package variety
+
+import (
+	"context"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	u "github.com/MrTimeout/go-home/backend/api/food/unit"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"gorm.io/gorm"
+)
+
+func addVariety(ctx context.Context, fv *FoodUnitVariety) error {
+	return config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		txx := u.JoinParents(u.WhereUnit(tx, fv.FoodUnit), fv.FoodUnit).Find(&fv.FoodUnit)
+		if txx.Error != nil {
+			return txx.Error
+		} else if fv.FoodUnit.ID == 0 {
+			return utils.NewProblemError(utils.ParentMissing, u.ErrUnitsNotFound)
+		}
+
+		return tx.Create(fv).Error
+	})
+}
+
+// UpsertVariety creates the variety fv of the unit fv.FoodUnit, found by name, or updates the one
+// with the same name restoring it from the trash if needed. The variety is moved if the unit changes.
+// It is used to load the catalog in bulk, so it can be run several times.
+// In utils.Insert mode, it fails with utils.ErrAlreadyExists instead of updating.
+func UpsertVariety(ctx context.Context, fv FoodUnitVariety, mode utils.Mode) (outcome utils.Outcome, err error) {
+	if err = utils.Validate(fv); err != nil {
+		return outcome, err
+	}
+
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		var current FoodUnitVariety
+
+		unit := u.FoodUnit{Name: fv.FoodUnit.Name}
+		if txx := u.JoinParents(u.WhereUnit(tx, unit), unit).Find(&unit); txx.Error != nil {
+			return txx.Error
+		} else if unit.ID == 0 {
+			return utils.NewProblemError(utils.ParentMissing, u.ErrUnitsNotFound)
+		}
+
+		fv.FoodUnitID, fv.FoodUnit = unit.ID, unit
+
+		if txx := tx.Unscoped().Where("name = ?", fv.Name).Order(utils.ActiveFirst).Find(&current); txx.Error != nil {
+			return txx.Error
+		} else if current.ID == 0 {
+			outcome = utils.Created
+			return tx.Create(&fv).Error
+		} else if mode == utils.Insert {
+			return utils.ErrAlreadyExists
+		} else if current.Description == fv.Description && current.Img == fv.Img &&
+			current.FoodUnitID == fv.FoodUnitID && !current.DeletedAt.Valid {
+			outcome = utils.Unchanged
+			return nil
+		}
+
+		outcome = utils.Updated
+		current.Description, current.Img, current.FoodUnitID, current.DeletedAt = fv.Description, fv.Img, fv.FoodUnitID, gorm.DeletedAt{}
+
+		return tx.Unscoped().Model(&current).Select("description", "img", "food_unit_id", "deleted_at").Updates(&current).Error
+	})
+
+	return outcome, err
+}
+
+// delVariety moves the varieties found by fv to the trash. Varieties have no children, so
+// opts.Cascade makes no difference.
+func delVariety(ctx context.Context, fv FoodUnitVariety, opts utils.DeleteOptions) (result utils.DeleteResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := varietyNodes(tx, fv, false)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return ErrVarietiesNotFound
+		}
+
+		result, err = utils.DeleteTree(tx, config.GetDrySession(ctx), ca.Hierarchy[3:], roots, opts)
+		return err
+	})
+
+	return result, err
+}
+
+// restoreVariety restores the varieties found by fv from the trash.
+func restoreVariety(ctx context.Context, fv FoodUnitVariety) (result utils.RestoreResult, err error) {
+	err = config.GetInstance(ctx).Transaction(func(tx *gorm.DB) error {
+		roots, err := varietyNodes(tx, fv, true)
+		if err != nil {
+			return err
+		} else if len(roots) == 0 {
+			return utils.ErrNotInTrash
+		}
+
+		result, err = utils.RestoreTree(tx, ca.Hierarchy[3:], roots)
+		return err
+	})
+
+	return result, err
+}
+
+// varietyNodes returns the varieties found by fv which are in the trash if deleted is true,
+// or the ones which are not otherwise.
+func varietyNodes(tx *gorm.DB, fv FoodUnitVariety, deleted bool) ([]utils.Node, error) {
+	var nodes []utils.Node
+
+	tx = filterVarieties(tx.Table(fv.TableName()), fv).
+		Where(utils.TrashCondition(fv.TableName(), deleted)).
+		Select(fv.TableName()+".food_unit_variety_id AS id", fv.TableName()+".name", fv.TableName()+".deleted_at").
+		Find(&nodes)
+
+	return nodes, tx.Error
+}
+
+func getVarieties(ctx context.Context, wrap utils.WrapperRequest[FoodUnitVariety]) ([]FoodUnitVariety, error) {
+	var result []FoodUnitVariety
+
+	tx := filterVarieties(wrap.ToScope(config.GetInstance(ctx)), wrap.Body).Find(&result)
+
+	return result, tx.Error
+}
+
+func countVarieties(ctx context.Context, wrap utils.WrapperRequest[FoodUnitVariety]) (total int64, err error) {
+	tx := filterVarieties(wrap.ToFilter(config.GetInstance(ctx).Model(&FoodUnitVariety{})), wrap.Body).Count(&total)
+	return total, tx.Error
+}
+
+func filterVarieties(db *gorm.DB, fv FoodUnitVariety) *gorm.DB {
+	return u.JoinParents(JoinUnits(WhereVarieties(db, fv), fv), fv.FoodUnit)
+}
+
+func WhereVarieties(db *gorm.DB, fv FoodUnitVariety) *gorm.DB {
+	if fv.ID != 0 {
+		db = db.Where(fv.TableName()+".food_unit_variety_id = ?", fv.ID)
+	}
+
+	if fv.Name != "" {
+		db = db.Where(fv.TableName()+".name = ?", fv.Name)
+	}
+
+	if fv.Description != "" {
+		db = db.Where(fv.TableName()+".description like ?", fv.Description)
+	}
+
+	return db
+}
+
+// JoinUnits joins the units of the varieties, skipping the ones in the trash.
+func JoinUnits(db *gorm.DB, fv FoodUnitVariety) *gorm.DB {
+	db = db.Joins("JOIN " + fv.FoodUnit.TableName() + " USING(food_unit_id)").
+		Where(fv.FoodUnit.TableName() + ".deleted_at IS NULL")
+	return u.WhereUnit(db, fv.FoodUnit)
+}
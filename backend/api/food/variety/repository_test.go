@@ -0,0 +1,91 @@
+package variety
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
+	u "github.com/MrTimeout/go-home/backend/api/food/unit"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGetVarietiesQuery(t *testing.T) {
+	db := newDryDB(t)
+
+	var queries []string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:record", func(tx *gorm.DB) {
+		queries = append(queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}))
+
+	ctx := config.WithTransaction(context.Background(), db)
+	fv := FoodUnitVariety{FoodUnit: u.FoodUnit{
+		Name: "apple",
+		FoodSubcategory: sca.FoodSubcategory{
+			Name:         "apples",
+			FoodCategory: ca.FoodCategory{Name: "fruits"},
+		},
+	}}
+
+	_, err := getVarieties(ctx, utils.WrapperRequest[FoodUnitVariety]{Limit: 10, Body: fv})
+	require.NoError(t, err)
+	_, err = countVarieties(ctx, utils.WrapperRequest[FoodUnitVariety]{Limit: 10, Body: fv})
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	for _, each := range queries {
+		assert.Contains(t, each, "JOIN food_units USING(food_unit_id)")
+		assert.Contains(t, each, "food_units.deleted_at IS NULL")
+		assert.Contains(t, each, "food_units.name = 'apple'")
+		assert.Contains(t, each, "food_categories.deleted_at IS NULL")
+		assert.Contains(t, each, "food_categories.name = 'fruits'")
+		assert.Contains(t, each, `"food_unit_varieties"."deleted_at" IS NULL`)
+	}
+}
+
+func TestDelVarietyRepository(t *testing.T) {
+	db := newDryDB(t, FoodUnitVariety{}.TableName())
+
+	var statements []string
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:record", func(tx *gorm.DB) {
+		if stmt := tx.Statement.SQL.String(); strings.HasPrefix(stmt, "UPDATE ") {
+			statements = append(statements, stmt)
+		}
+	}))
+
+	ctx := config.WithTransaction(context.Background(), db)
+	fv := FoodUnitVariety{Name: "granny smith", FoodUnit: u.FoodUnit{Name: "apple"}}
+
+	t.Run("varieties have no children, so they are deleted without cascading", func(t *testing.T) {
+		statements = nil
+
+		result, err := delVariety(ctx, fv, utils.DeleteOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Rows)
+		require.Len(t, statements, 1)
+		assert.Equal(t, "UPDATE food_unit_varieties SET deleted_at = $1 WHERE food_unit_variety_id IN ($2)", statements[0])
+	})
+
+	t.Run("a dry run returns the statements with their values", func(t *testing.T) {
+		result, err := delVariety(ctx, fv, utils.DeleteOptions{DryRun: true})
+
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, int64(1), result.Rows)
+		require.Len(t, result.SQL, 1)
+		assert.Contains(t, result.SQL[0], "UPDATE food_unit_varieties SET deleted_at = '")
+		assert.Contains(t, result.SQL[0], "WHERE food_unit_variety_id IN (1)")
+	})
+
+	t.Run("missing varieties are not found", func(t *testing.T) {
+		_, err := delVariety(config.WithTransaction(context.Background(), newDryDB(t)), fv, utils.DeleteOptions{})
+
+		assert.ErrorIs(t, err, ErrVarietiesNotFound)
+	})
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// LivenessPath reports whether the process is alive.
+	// /healthz
+	LivenessPath = "/healthz"
+	// ReadinessPath reports whether the API can serve requests.
+	// /readyz
+	ReadinessPath = "/readyz"
+	// StartupPath reports whether the API finished starting.
+	// /startupz
+	StartupPath = "/startupz"
+
+	// HealthPath is the prefix of the routes which report the health of the dependencies of the API.
+	HealthPath = "/health"
+
+	// DatabasePath reports whether the database can be reached, along with the stats of its pool of connections.
+	// /health/database
+	DatabasePath = "/database"
+
+	// PingTimeout is how long the database is waited for when checking its health.
+	PingTimeout = 2 * time.Second
+	// DefaultTimeout is how long a check is waited for when its Checker has no timeout.
+	DefaultTimeout = 2 * time.Second
+)
+
+// Paths are the routes of each probe.
+var Paths = map[Probe]string{
+	Liveness:  LivenessPath,
+	Readiness: ReadinessPath,
+	Startup:   StartupPath,
+}
+
+// GetLiveness responds with the health of the process, which is up while it can respond.
+func GetLiveness(c *gin.Context) {
+	report(c, Liveness)
+}
+
+// GetReadiness responds with whether the API can serve requests.
+func GetReadiness(c *gin.Context) {
+	report(c, Readiness)
+}
+
+// GetStartup responds with whether the API finished starting.
+func GetStartup(c *gin.Context) {
+	report(c, Startup)
+}
+
+// report responds with the Report of probe, being 503 Service Unavailable if it is down.
+func report(c *gin.Context, probe Probe) {
+	status, report := http.StatusOK, Run(c.Request.Context(), probe)
+	if report.Status == Down {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.Negotiate(status, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    report,
+	})
+}
+
+// GetDatabase responds with the health of the database, being 503 Service Unavailable if it
+// can't be reached. The error is logged instead of responded, as it may describe the network.
+func GetDatabase(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), PingTimeout)
+	defer cancel()
+
+	status := http.StatusOK
+
+	health, err := CheckDatabase(ctx)
+	if err != nil {
+		config.LoggerFrom(ctx).Warn("database is down", zap.Error(err))
+		status = http.StatusServiceUnavailable
+	}
+
+	c.Negotiate(status, gin.Negotiate{
+		Offered: utils.Negotiate,
+		Data:    health,
+	})
+}
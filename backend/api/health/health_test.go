@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	config.ConfigureLogger(config.Logger{})
+	t.Cleanup(func() {
+		checkers = make(map[Probe][]Checker)
+	})
+
+	up := func(context.Context) error { return nil }
+
+	Register(Readiness, Checker{Name: "b", Check: up})
+	Register(Readiness, Checker{Name: "a", Check: up})
+
+	assert.Equal(t, Up, Run(context.Background(), Readiness).Status)
+	assert.Equal(t, Report{Status: Up, Checks: []Check{}}, Run(context.Background(), Liveness))
+
+	Register(Readiness, Checker{Name: "b", Check: func(context.Context) error { return errors.New("down") }})
+	Register(Readiness, Checker{Name: "c", Timeout: time.Millisecond, Check: func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}})
+
+	report := Run(context.Background(), Readiness)
+
+	assert.Equal(t, Down, report.Status)
+	assert.Len(t, report.Checks, 3)
+	for i, want := range []Check{{Name: "a", Status: Up}, {Name: "b", Status: Down}, {Name: "c", Status: Down}} {
+		assert.Equal(t, want.Name, report.Checks[i].Name)
+		assert.Equal(t, want.Status, report.Checks[i].Status)
+	}
+}
+
+func TestGetStartup(t *testing.T) {
+	config.ConfigureLogger(config.Logger{})
+	t.Cleanup(func() {
+		checkers = make(map[Probe][]Checker)
+		started.Store(false)
+	})
+
+	Register(Startup, Checker{Name: "started", Check: CheckStarted})
+
+	router := gin.New()
+	router.GET(StartupPath, GetStartup)
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, StartupPath, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, get().Code)
+
+	MarkStarted()
+
+	w := get()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"up"`)
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"encoding/xml"
+	"time"
+)
+
+// Status is whether a dependency of the API can be used.
+type Status string
+
+const (
+	// Up is used when the dependency can be used.
+	Up Status = "up"
+	// Down is used when the dependency can't be used.
+	Down Status = "down"
+)
+
+// Probe is the kind of health check made by the container orchestrator.
+type Probe string
+
+const (
+	// Liveness checks whether the process is alive, so it is restarted otherwise.
+	Liveness Probe = "liveness"
+	// Readiness checks whether the API can serve requests, so it receives them only then.
+	Readiness Probe = "readiness"
+	// Startup checks whether the API finished starting, so the other probes are made only then.
+	Startup Probe = "startup"
+)
+
+// CheckFunc returns an error if what it checks is not healthy. It should return when ctx is done.
+type CheckFunc func(ctx context.Context) error
+
+// Checker is a named check of a probe, which fails if it takes longer than its timeout.
+type Checker struct {
+	Name string
+	// Timeout is how long the check is waited for, DefaultTimeout if it is 0.
+	Timeout time.Duration
+	Check   CheckFunc
+}
+
+// Report
+//
+// It is the health of the API for a probe, which is down if any of its checks is down.
+//
+// swagger:model health-report
+type Report struct {
+	// swagger:ignore
+	XMLName xml.Name `json:"-" yaml:"-" xml:"Health"`
+	// Status is up if all the checks are up
+	//
+	// enum: up,down
+	// example: up
+	Status Status `json:"status" yaml:"status" xml:"Status"`
+	// Checks are the results of the checks of the probe, ordered by name
+	Checks []Check `json:"checks,omitempty" yaml:"checks,omitempty" xml:"Checks>Check,omitempty"`
+}
+
+// Check
+//
+// It is the result of a check of a probe. The error is logged instead of reported.
+//
+// swagger:model health-check
+type Check struct {
+	// Name of the check
+	//
+	// example: database
+	Name string `json:"name" yaml:"name" xml:"Name"`
+	// Status is whether the check passed
+	//
+	// enum: up,down
+	// example: up
+	Status Status `json:"status" yaml:"status" xml:"Status"`
+	// Duration is how long the check took
+	//
+	// example: 1.2ms
+	Duration string `json:"duration" yaml:"duration" xml:"Duration"`
+}
+
+// Database
+//
+// It is the health of the database along with the stats of its pool of connections.
+//
+// swagger:model database-health
+type Database struct {
+	// swagger:ignore
+	XMLName xml.Name `json:"-" yaml:"-" xml:"Database"`
+	// Status is whether the database answered the ping
+	//
+	// enum: up,down
+	// example: up
+	Status Status `json:"status" yaml:"status" xml:"Status"`
+	// MaxOpenConnections is the maximum amount of connections open, unlimited if it is 0
+	MaxOpenConnections int `json:"max_open_connections" yaml:"max_open_connections" xml:"MaxOpenConnections"`
+	// OpenConnections is the amount of connections open, in use or idle
+	OpenConnections int `json:"open_connections" yaml:"open_connections" xml:"OpenConnections"`
+	// InUse is the amount of connections in use
+	InUse int `json:"in_use" yaml:"in_use" xml:"InUse"`
+	// Idle is the amount of idle connections
+	Idle int `json:"idle" yaml:"idle" xml:"Idle"`
+	// WaitCount is how many times a connection was waited for
+	WaitCount int64 `json:"wait_count" yaml:"wait_count" xml:"WaitCount"`
+	// WaitDuration is how long the connections were waited for in total
+	//
+	// example: 1.5s
+	WaitDuration string `json:"wait_duration" yaml:"wait_duration" xml:"WaitDuration"`
+	// MaxIdleClosed is how many connections were closed because of the maximum amount of idle connections
+	MaxIdleClosed int64 `json:"max_idle_closed" yaml:"max_idle_closed" xml:"MaxIdleClosed"`
+	// MaxIdleTimeClosed is how many connections were closed because of the maximum idle time
+	MaxIdleTimeClosed int64 `json:"max_idle_time_closed" yaml:"max_idle_time_closed" xml:"MaxIdleTimeClosed"`
+	// MaxLifetimeClosed is how many connections were closed because of the maximum lifetime
+	MaxLifetimeClosed int64 `json:"max_lifetime_closed" yaml:"max_lifetime_closed" xml:"MaxLifetimeClosed"`
+}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrTimeout is used when a check takes longer than its timeout.
+	ErrTimeout = errors.New("health check timed out")
+	// ErrNotStarted is used by CheckStarted until MarkStarted is called.
+	ErrNotStarted = errors.New("API has not started yet")
+
+	checkersMu sync.RWMutex
+	checkers   = make(map[Probe][]Checker)
+
+	started atomic.Bool
+)
+
+// Register adds checker to the checks of probe, replacing the one with the same name.
+func Register(probe Probe, checker Checker) {
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
+
+	for i, each := range checkers[probe] {
+		if each.Name == checker.Name {
+			checkers[probe][i] = checker
+			return
+		}
+	}
+
+	checkers[probe] = append(checkers[probe], checker)
+}
+
+// Run runs the checks of probe at once, logging the errors of the ones which fail.
+func Run(ctx context.Context, probe Probe) Report {
+	checkersMu.RLock()
+	registered := append([]Checker(nil), checkers[probe]...)
+	checkersMu.RUnlock()
+
+	report := Report{Status: Up, Checks: make([]Check, len(registered))}
+
+	var wg sync.WaitGroup
+	for i, each := range registered {
+		wg.Add(1)
+
+		go func(i int, checker Checker) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := run(ctx, checker)
+
+			report.Checks[i] = Check{Name: checker.Name, Status: Up, Duration: time.Since(start).String()}
+			if err != nil {
+				report.Checks[i].Status = Down
+				config.LoggerFrom(ctx).Warn("health check failed", zap.String("probe", string(probe)), zap.String("check", checker.Name), zap.Error(err))
+			}
+		}(i, each)
+	}
+
+	wg.Wait()
+
+	sort.Slice(report.Checks, func(i, j int) bool {
+		return report.Checks[i].Name < report.Checks[j].Name
+	})
+
+	for _, each := range report.Checks {
+		if each.Status == Down {
+			report.Status = Down
+		}
+	}
+
+	return report
+}
+
+// run returns the error of checker, which is ErrTimeout if it takes longer than its timeout even
+// if it doesn't return when its context is done.
+func run(ctx context.Context, checker Checker) error {
+	timeout := checker.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+}
+
+// MarkStarted makes CheckStarted pass, once the API finished starting.
+func MarkStarted() {
+	started.Store(true)
+}
+
+// CheckStarted returns ErrNotStarted until MarkStarted is called.
+func CheckStarted(context.Context) error {
+	if !started.Load() {
+		return ErrNotStarted
+	}
+
+	return nil
+}
+
+// CheckDatabase pings the database, returning its health along with the stats of its pool of
+// connections. The error is the one of the ping.
+func CheckDatabase(ctx context.Context) (Database, error) {
+	err := config.PingDB(ctx)
+	stats := config.DBStats()
+
+	status := Up
+	if err != nil {
+		status = Down
+	}
+
+	return Database{
+		Status:             status,
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, err
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// defaultMethods are the methods allowed when CORS doesn't set them, which are all the ones of the API.
+	defaultMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	// defaultHeaders are the headers allowed when CORS doesn't set them.
+	defaultHeaders = []string{"Accept", "Accept-Language", "Content-Type"}
+	// defaultExposedHeaders are the headers exposed when CORS doesn't set them: the ones of the
+	// pagination and the ID of the request.
+	defaultExposedHeaders = []string{utils.TotalCountHeader, utils.LinkHeader, RequestIDHeader}
+)
+
+// CORS allows the cross-origin requests from the origins of the current config.CORS, answering
+// the preflight requests itself. The configuration is read on each request, so it can be reloaded.
+func CORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg, origin := config.Current().CORS, c.GetHeader("Origin")
+		if origin == "" || len(cfg.AllowedOrigins) == 0 {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+
+		allowed, ok := allowedOrigin(cfg, origin)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", allowed)
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if c.Request.Method != http.MethodOptions || c.GetHeader("Access-Control-Request-Method") == "" {
+			exposed := cfg.ExposedHeaders
+			if len(exposed) == 0 {
+				exposed = defaultExposedHeaders
+			}
+
+			c.Header("Access-Control-Expose-Headers", strings.Join(exposed, ", "))
+			c.Next()
+			return
+		}
+
+		methods, headers := cfg.AllowedMethods, cfg.AllowedHeaders
+		if len(methods) == 0 {
+			methods = defaultMethods
+		}
+
+		if len(headers) == 0 {
+			headers = defaultHeaders
+		}
+
+		c.Header("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+		c.Header("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+		if cfg.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+		}
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allowedOrigin returns the value of Access-Control-Allow-Origin for origin, which is * only
+// if any origin is allowed.
+func allowedOrigin(cfg config.CORS, origin string) (string, bool) {
+	for _, each := range cfg.AllowedOrigins {
+		if each == "*" {
+			return each, true
+		} else if strings.EqualFold(strings.TrimSuffix(each, "/"), origin) {
+			return origin, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// redacted replaces the values of the headers which are not logged.
+	redacted = "xxxxx"
+	// unmatchedRoute is the route logged for the requests which don't match any route.
+	unmatchedRoute = "unmatched"
+)
+
+// Logger logs each request of the current config.AccessLog once it is responded: at error level
+// if it fails with 5xx, at warn level if it fails with 4xx and at info level, sampled, otherwise.
+// It uses the logger of the context of the request, which carries its ID after RequestID. The
+// configuration is read on each request, so it can be reloaded.
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		cfg := config.Current().AccessLog
+		if !cfg.Enabled || excluded(cfg.ExcludePaths, c.Request.URL.Path) {
+			return
+		}
+
+		status := c.Writer.Status()
+		if status < http.StatusBadRequest && rand.Float64() >= cfg.SampleRate { //nolint:gosec
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("bytes", size),
+		}
+
+		if cfg.Headers {
+			fields = append(fields, zap.Any("headers", redactHeaders(c.Request.Header, cfg.RedactHeaders)))
+		}
+
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.Strings("errors", c.Errors.Errors()))
+		}
+
+		logger := config.LoggerFrom(c.Request.Context())
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Error("request", fields...)
+		case status >= http.StatusBadRequest:
+			logger.Warn("request", fields...)
+		default:
+			logger.Info("request", fields...)
+		}
+	}
+}
+
+// excluded returns whether path is one of paths.
+func excluded(paths []string, path string) bool {
+	for _, each := range paths {
+		if each == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactHeaders returns the headers joined by comma, replacing the values of the ones of redact.
+func redactHeaders(headers http.Header, redact []string) map[string]string {
+	result := make(map[string]string, len(headers))
+	for name, values := range headers {
+		result[name] = strings.Join(values, ", ")
+	}
+
+	for _, each := range redact {
+		name := http.CanonicalHeaderKey(each)
+		if _, ok := result[name]; ok {
+			result[name] = redacted
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(cfg config.Config) *gin.Engine {
+	config.SetCurrent(cfg)
+
+	router := gin.New()
+	router.Use(CORS(), RateLimit())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router
+}
+
+func do(router *gin.Engine, method string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestCORS(t *testing.T) {
+	t.Cleanup(func() { config.SetCurrent(config.Config{}) })
+
+	router := newRouter(config.Config{CORS: config.CORS{AllowedOrigins: []string{"https://home.lan"}, MaxAge: time.Hour}})
+
+	t.Run("preflight of an origin allowed", func(t *testing.T) {
+		w := do(router, http.MethodOptions, map[string]string{"Origin": "https://home.lan", "Access-Control-Request-Method": "PUT"})
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://home.lan", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST, PUT, PATCH, DELETE", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("request of an origin not allowed", func(t *testing.T) {
+		w := do(router, http.MethodGet, map[string]string{"Origin": "https://evil.example"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("request of an origin allowed exposes the pagination headers", func(t *testing.T) {
+		w := do(router, http.MethodGet, map[string]string{"Origin": "https://home.lan"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "X-Total-Count, Link, X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("exposed headers are reloaded", func(t *testing.T) {
+		config.SetCurrent(config.Config{CORS: config.CORS{AllowedOrigins: []string{"https://home.lan"}, ExposedHeaders: []string{"Retry-After"}}})
+
+		w := do(router, http.MethodGet, map[string]string{"Origin": "https://home.lan"})
+
+		assert.Equal(t, "Retry-After", w.Header().Get("Access-Control-Expose-Headers"))
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Cleanup(func() { config.SetCurrent(config.Config{}) })
+
+	router := newRouter(config.Config{RateLimit: config.RateLimit{RequestsPerSecond: 1, Burst: 2}})
+
+	assert.Equal(t, http.StatusOK, do(router, http.MethodGet, nil).Code)
+	assert.Equal(t, http.StatusOK, do(router, http.MethodGet, nil).Code)
+
+	w := do(router, http.MethodGet, nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	config.SetCurrent(config.Config{})
+	assert.Equal(t, http.StatusOK, do(router, http.MethodGet, nil).Code)
+}
+
+func TestLimiterRefill(t *testing.T) {
+	var (
+		l     limiter
+		limit = config.RateLimit{RequestsPerSecond: 2}
+		now   = time.Now()
+	)
+
+	for i := 0; i < 2; i++ {
+		_, ok := l.allow(limit, "client", now)
+		assert.True(t, ok)
+	}
+
+	wait, ok := l.allow(limit, "client", now)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	_, ok = l.allow(limit, "client", now.Add(wait))
+	assert.True(t, ok)
+}
+
+func TestLogger(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "go-home.log")
+	config.ConfigureLogger(config.Logger{
+		Production:    true,
+		FileAppenders: []config.FileLoggerAppender{config.NewFileLoggerAppender(config.DebugLevel, file, config.RFC3339)},
+	})
+	t.Cleanup(func() {
+		config.SetCurrent(config.Config{})
+		config.ConfigureLogger(config.Logger{})
+	})
+
+	config.SetCurrent(config.Config{AccessLog: config.AccessLog{
+		Enabled:       true,
+		ExcludePaths:  []string{"/healthz"},
+		Headers:       true,
+		RedactHeaders: []string{"authorization"},
+	}})
+
+	router := gin.New()
+	router.Use(RequestID(), Logger())
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/food/categories/:category", func(c *gin.Context) { c.String(http.StatusOK, "fruits") })
+	router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	for _, each := range []string{"/healthz", "/food/categories/fruits", "/fail"} {
+		req := httptest.NewRequest(http.MethodGet, each, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set(RequestIDHeader, "42")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+
+	require.Len(t, entries, 1, "successful requests are sampled out with a sample rate of 0")
+	assert.Equal(t, "error", entries[0]["level"])
+	assert.Equal(t, "/fail", entries[0]["route"])
+	assert.Equal(t, "42", entries[0]["request_id"])
+	assert.Equal(t, map[string]any{"Authorization": "xxxxx", "X-Request-Id": "42"}, entries[0]["headers"])
+	assert.NotContains(t, string(b), "secret")
+
+	config.SetCurrent(config.Config{AccessLog: config.AccessLog{Enabled: true, SampleRate: 1}})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/food/categories/fruits", nil))
+
+	b, err = os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"route":"/food/categories/:category"`)
+	assert.Contains(t, string(b), `"bytes":6`)
+}
+
+func TestRequestID(t *testing.T) {
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, config.RequestID(c.Request.Context())) })
+
+	for _, each := range []struct {
+		description, header string
+		wantGenerated       bool
+	}{
+		{description: "request ID of the client is kept", header: "abc-42"},
+		{description: "missing request ID is generated", wantGenerated: true},
+		{description: "invalid request ID is replaced", header: "abc\n42", wantGenerated: true},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			w := do(router, http.MethodGet, map[string]string{RequestIDHeader: each.header})
+
+			got := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, got, w.Body.String())
+
+			if each.wantGenerated {
+				assert.Regexp(t, `^[0-9a-f]{32}$`, got)
+			} else {
+				assert.Equal(t, each.header, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+)
+
+// idleBuckets is how long the bucket of a client is kept since its last request.
+const idleBuckets = 10 * time.Minute
+
+// ErrRateLimited is used when a client exceeds the rate limit.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// bucket is the token bucket of a client: it holds up to burst tokens, which are refilled at the
+// rate of the limit, and each request takes one of them.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter keeps the buckets of the clients of a config.RateLimit.
+type limiter struct {
+	mu      sync.Mutex
+	limit   config.RateLimit
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// RateLimit responds with 429 Too Many Requests to the clients, found by IP, which exceed the
+// current config.RateLimit. The configuration is read on each request, so it can be reloaded,
+// and the clients start again with all the burst when it changes.
+func RateLimit() gin.HandlerFunc {
+	l := &limiter{}
+
+	return func(c *gin.Context) {
+		limit := config.Current().RateLimit
+		if limit.RequestsPerSecond == 0 {
+			c.Next()
+			return
+		}
+
+		if wait, ok := l.allow(limit, c.ClientIP(), time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			utils.ErrRes(c, utils.NewProblemError(utils.TooManyRequests, ErrRateLimited), http.StatusTooManyRequests)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allow takes a token of the bucket of client, returning false along with how long it has to wait
+// for the next one if there are none left.
+func (l *limiter) allow(limit config.RateLimit, client string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	burst := float64(limit.Burst)
+	if burst == 0 {
+		burst = math.Ceil(limit.RequestsPerSecond)
+	}
+
+	if l.buckets == nil || l.limit != limit {
+		l.limit, l.buckets, l.swept = limit, make(map[string]*bucket), now
+	} else if now.Sub(l.swept) > idleBuckets {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[client] = b
+	}
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.RequestsPerSecond)
+	b.last = now
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / limit.RequestsPerSecond * float64(time.Second)), false
+	}
+
+	b.tokens--
+
+	return 0, true
+}
+
+// sweep removes the buckets of the clients which have been idle for a while.
+func (l *limiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		if now.Sub(b.last) > idleBuckets {
+			delete(l.buckets, client)
+		}
+	}
+
+	l.swept = now
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header which identifies a request, in the request and in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID matches the request IDs accepted from the clients, so they can't inject
+// anything into the logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._~:+/=-]{1,128}$`)
+
+// RequestID identifies each request by the X-Request-ID header of the client or, if it has none
+// or it is not valid, by a new random one. The ID is returned in the X-Request-ID header of the
+// response, and the context of the request carries it along with a logger which adds it to every
+// message, so the logs of the queries made by the request can be found.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(config.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+	}
+}
+
+// newRequestID returns 16 random bytes encoded as hexadecimal.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b) //nolint:errcheck
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"github.com/MrTimeout/go-home/backend/api/admin"
+	"github.com/MrTimeout/go-home/backend/api/food/bulk"
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	"github.com/MrTimeout/go-home/backend/api/food/search"
+	sca "github.com/MrTimeout/go-home/backend/api/food/subcategory"
+	"github.com/MrTimeout/go-home/backend/api/food/trash"
+	u "github.com/MrTimeout/go-home/backend/api/food/unit"
+	v "github.com/MrTimeout/go-home/backend/api/food/variety"
+	"github.com/MrTimeout/go-home/backend/api/health"
+	"github.com/MrTimeout/go-home/backend/api/middleware"
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/MrTimeout/go-home/backend/internals/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// FoodPath is the prefix of all the routes of the food catalog.
+const FoodPath = "/food"
+
+// NewRouter returns the router with all the routes of the API. reload is used to reload the
+// configuration through the admin routes. The metrics are served if they are enabled by the
+// current configuration, and the IP of the clients is only taken from the headers of its
+// server.trusted_proxies.
+func NewRouter(reload admin.ReloadFunc) *gin.Engine {
+	router := gin.New()
+	// The trusted proxies are checked by config.Validate. Otherwise, gin trusts none of them.
+	router.SetTrustedProxies(config.Current().Server.TrustedProxies) //nolint:errcheck
+	router.Use(middleware.RequestID(), middleware.Logger())
+
+	// The probes and the metrics are added before the other middlewares, so the orchestrator and
+	// Prometheus are never rate limited. They are logged unless they are in access_log.exclude_paths,
+	// as they are by default.
+	router.GET(health.LivenessPath, health.GetLiveness)
+	router.GET(health.ReadinessPath, health.GetReadiness)
+	router.GET(health.StartupPath, health.GetStartup)
+
+	if m := config.Current().Metrics; m.Enabled {
+		router.GET(m.Path, gin.WrapH(metrics.Handler()))
+	}
+
+	router.Use(metrics.Middleware(), middleware.CORS(), middleware.RateLimit())
+
+	router.POST(admin.AdminPath+admin.ConfigReloadPath, admin.ReloadConfig(reload))
+	router.GET(health.HealthPath+health.DatabasePath, health.GetDatabase)
+
+	food := router.Group(FoodPath)
+	{
+		food.GET(ca.CategoriesPath, ca.GetCategories)
+		food.POST(ca.CategoriesPath, ca.AddCategory)
+		food.GET(ca.CategoryByNamePath, ca.GetCategoryByName)
+		food.PUT(ca.CategoryByNamePath, ca.UpdateCategory)
+		food.PATCH(ca.CategoryByNamePath, ca.PatchCategory)
+		food.DELETE(ca.CategoryByNamePath, ca.DelCategory)
+		food.POST(ca.CategoryRestorePath, ca.RestoreCategory)
+
+		food.GET(sca.SubcategoriesPath, sca.GetSubcategories)
+		food.POST(sca.SubcategoriesPath, sca.AddSubcategory)
+		food.GET(sca.SubcategoryByNamePath, sca.GetSubcategoryByName)
+		food.PUT(sca.SubcategoryByNamePath, sca.UpdateSubcategory)
+		food.PATCH(sca.SubcategoryByNamePath, sca.PatchSubcategory)
+		food.DELETE(sca.SubcategoryByNamePath, sca.DelSubcategory)
+		food.POST(sca.SubcategoryRestorePath, sca.RestoreSubcategory)
+
+		food.GET(u.UnitsBySubcategoriesPath, u.GetUnitsBySubcategory)
+		food.POST(u.UnitsBySubcategoriesPath, u.AddUnit)
+		food.GET(u.UnitBySubcategoryPath, u.GetUnitBySubcategory)
+		food.PUT(u.UnitBySubcategoryPath, u.UpdateUnit)
+		food.PATCH(u.UnitBySubcategoryPath, u.PatchUnit)
+		food.DELETE(u.UnitBySubcategoryPath, u.DelUnit)
+		food.POST(u.UnitRestoreBySubcategoryPath, u.RestoreUnit)
+
+		food.GET(u.UnitsByCategoriesPath, u.GetUnitsByCategory)
+		food.GET(u.UnitByCategoriesPath, u.GetUnitByCategory)
+
+		food.GET(v.VarietiesBySubcategoryPath, v.GetVarieties)
+		food.POST(v.VarietiesBySubcategoryPath, v.AddVariety)
+		food.GET(v.VarietyBySubcategoryPath, v.GetVarietyByName)
+		food.DELETE(v.VarietyBySubcategoryPath, v.DelVariety)
+		food.POST(v.VarietyRestoreBySubcategoryPath, v.RestoreVariety)
+
+		food.GET(v.VarietiesByCategoryPath, v.GetVarieties)
+		food.POST(v.VarietiesByCategoryPath, v.AddVariety)
+		food.GET(v.VarietyByCategoryPath, v.GetVarietyByName)
+		food.DELETE(v.VarietyByCategoryPath, v.DelVariety)
+		food.POST(v.VarietyRestoreByCategoryPath, v.RestoreVariety)
+
+		food.GET(search.SearchPath, search.Search)
+		food.GET(trash.TrashPath, trash.GetTrash)
+
+		food.POST(bulk.ImportPath, bulk.ImportCatalog)
+		food.GET(bulk.ExportPath, bulk.ExportCatalog)
+	}
+
+	return router
+}
@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// idOrderBy is the order by field appended to every ordering as a tiebreaker, so
+// each row has a unique position which can be pointed by a cursor.
+const idOrderBy = "id"
+
+var (
+	// ErrInvalidCursor is used when the cursor query is not one returned by a page of the same
+	// ordering, because it was tampered with or it is stale.
+	ErrInvalidCursor = errors.New("cursor query must be the next_cursor or prev_cursor of a page with the same order_by")
+
+	schemaCache sync.Map
+)
+
+// cursor is the position of a row inside of an ordered list of rows. It is used to
+// paginate using the ordering values of the last row seen instead of an offset, which
+// is stable when rows are inserted and doesn't force the database to scan skipped rows.
+type cursor struct {
+	// OrderBy is the ordering used when the cursor was created.
+	OrderBy []string `json:"o"`
+	// Values are the values of the ordering columns of the row.
+	Values []any `json:"v"`
+	// Backward is true when the rows before the cursor are requested.
+	Backward bool `json:"b,omitempty"`
+}
+
+// String returns the opaque representation of the cursor sent to the clients.
+func (c cursor) String() string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func parseCursor(input string, keyset []orderBy) (*cursor, error) {
+	var c cursor
+
+	b, err := base64.RawURLEncoding.DecodeString(input)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	if err := d.Decode(&c); err != nil || len(c.Values) != len(keyset) || len(c.OrderBy) != len(keyset) {
+		return nil, ErrInvalidCursor
+	}
+
+	for i := range keyset {
+		if c.OrderBy[i] != keyset[i].String() {
+			return nil, ErrInvalidCursor
+		}
+
+		if n, ok := c.Values[i].(json.Number); ok {
+			c.Values[i] = parseJSONNumber(n)
+		}
+	}
+
+	return &c, nil
+}
+
+func parseJSONNumber(n json.Number) any {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+
+	return n.String()
+}
+
+// keyset returns the ordering of the request: the allowed order by fields followed by
+// the id as tiebreaker, when it is allowed.
+func (w WrapperRequest[T]) keyset() []orderBy {
+	var (
+		cols   = w.Body.OrderByColumnsAllowed()
+		result = make([]orderBy, 0, len(w.OrderBy)+1)
+		seen   = make(map[string]struct{}, len(w.OrderBy)+1)
+	)
+
+	for _, each := range w.OrderBy {
+		if _, ok := cols[each.Field]; !ok {
+			continue
+		}
+
+		if _, ok := seen[each.Field]; !ok {
+			seen[each.Field] = struct{}{}
+			result = append(result, each)
+		}
+	}
+
+	if _, ok := cols[idOrderBy]; ok {
+		if _, ok := seen[idOrderBy]; !ok {
+			result = append(result, orderBy{Field: idOrderBy, Direction: asc})
+		}
+	}
+
+	return result
+}
+
+// columnOf returns the column of the field. Fields can be mapped to the column through
+// the values of cols, otherwise the field is the column.
+func columnOf(cols map[string]any, field string) string {
+	if col, ok := cols[field].(string); ok {
+		return col
+	}
+
+	return field
+}
+
+// column returns the column of the order by field.
+func (w WrapperRequest[T]) column(field string) string {
+	return columnOf(w.Body.OrderByColumnsAllowed(), field)
+}
+
+// qualify prefixes the column with the table name, so it is not ambiguous when joining
+// other tables.
+func (w WrapperRequest[T]) qualify(column string) string {
+	if t, ok := any(w.Body).(schema.Tabler); ok {
+		return t.TableName() + "." + column
+	}
+
+	return column
+}
+
+// qualifiedColumn returns the qualified column of the order by field.
+func (w WrapperRequest[T]) qualifiedColumn(field string) string {
+	return w.qualify(w.column(field))
+}
+
+// keysetCondition returns the condition that filters the rows after the cursor,
+// or before it when it is a backward cursor.
+func (w WrapperRequest[T]) keysetCondition(keyset []orderBy) (string, []any) {
+	var (
+		ors  = make([]string, len(keyset))
+		args = make([]any, 0, len(keyset)*(len(keyset)+1)/2)
+	)
+
+	for i := range keyset {
+		ands := make([]string, i+1)
+
+		for j := 0; j < i; j++ {
+			ands[j] = w.qualifiedColumn(keyset[j].Field) + " = ?"
+			args = append(args, w.Cursor.Values[j])
+		}
+
+		op := " > ?"
+		if (keyset[i].Direction == desc) != w.Cursor.Backward {
+			op = " < ?"
+		}
+
+		ands[i] = w.qualifiedColumn(keyset[i].Field) + op
+		args = append(args, w.Cursor.Values[i])
+
+		ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// newCursor returns the cursor pointing to item. It returns nil when the values of the
+// ordering columns can't be read from item.
+func (w WrapperRequest[T]) newCursor(item T, backward bool) *cursor {
+	s, err := schema.Parse(&item, &schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil
+	}
+
+	var (
+		keyset = w.keyset()
+		c      = cursor{
+			OrderBy:  make([]string, len(keyset)),
+			Values:   make([]any, len(keyset)),
+			Backward: backward,
+		}
+	)
+
+	for i, each := range keyset {
+		f := s.LookUpField(w.column(each.Field))
+		if f == nil {
+			return nil
+		}
+
+		c.OrderBy[i] = each.String()
+		c.Values[i], _ = f.ValueOf(context.Background(), reflect.ValueOf(item))
+	}
+
+	return &c
+}
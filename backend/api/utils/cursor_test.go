@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cursorModel struct {
+	ID   int    `gorm:"column:model_id;primaryKey"`
+	Name string `gorm:"column:name"`
+}
+
+func (cursorModel) OrderByColumnsAllowed() map[string]any {
+	return map[string]any{"id": "model_id", "name": "name"}
+}
+
+func (cursorModel) TableName() string {
+	return "models"
+}
+
+func TestKeyset(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       []orderBy
+		want        []orderBy
+	}{
+		{
+			description: "id is used when there is no order by",
+			input:       []orderBy{},
+			want:        []orderBy{{Field: "id", Direction: asc}},
+		},
+		{
+			description: "id is appended as tiebreaker",
+			input:       []orderBy{{Field: "name", Direction: desc}},
+			want:        []orderBy{{Field: "name", Direction: desc}, {Field: "id", Direction: asc}},
+		},
+		{
+			description: "id is not appended if it is already present",
+			input:       []orderBy{{Field: "id", Direction: desc}, {Field: "name", Direction: asc}},
+			want:        []orderBy{{Field: "id", Direction: desc}, {Field: "name", Direction: asc}},
+		},
+		{
+			description: "not allowed and repeated fields are discarded",
+			input:       []orderBy{{Field: "unknown", Direction: desc}, {Field: "name", Direction: asc}, {Field: "name", Direction: desc}},
+			want:        []orderBy{{Field: "name", Direction: asc}, {Field: "id", Direction: asc}},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got := WrapperRequest[cursorModel]{OrderBy: each.input}.keyset()
+
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	keyset := []orderBy{{Field: "name", Direction: desc}, {Field: "id", Direction: asc}}
+
+	t.Run("cursor is parsed back with numbers as int64", func(t *testing.T) {
+		want := cursor{OrderBy: []string{"name DESC", "id ASC"}, Values: []any{"apple", int64(3)}, Backward: true}
+
+		got, err := parseCursor(want.String(), keyset)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &want, got)
+	})
+
+	for _, each := range []struct {
+		description, input string
+	}{
+		{description: "input is not base64", input: "not base64!"},
+		{description: "input is not a cursor", input: "bm90IGpzb24"},
+		{description: "ordering of the cursor is different", input: cursor{OrderBy: []string{"name ASC", "id ASC"}, Values: []any{"apple", 3}}.String()},
+		{description: "values are missing", input: cursor{OrderBy: []string{"name DESC", "id ASC"}, Values: []any{"apple"}}.String()},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			_, err := parseCursor(each.input, keyset)
+
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		cursor      cursor
+		orderBy     []orderBy
+		wantQuery   string
+		wantArgs    []any
+	}{
+		{
+			description: "forward cursor ordering by id",
+			cursor:      cursor{Values: []any{int64(3)}},
+			wantQuery:   "((models.model_id > ?))",
+			wantArgs:    []any{int64(3)},
+		},
+		{
+			description: "forward cursor ordering by name descending",
+			cursor:      cursor{Values: []any{"apple", int64(3)}},
+			orderBy:     []orderBy{{Field: "name", Direction: desc}},
+			wantQuery:   "((models.name < ?) OR (models.name = ? AND models.model_id > ?))",
+			wantArgs:    []any{"apple", "apple", int64(3)},
+		},
+		{
+			description: "backward cursor ordering by name descending",
+			cursor:      cursor{Values: []any{"apple", int64(3)}, Backward: true},
+			orderBy:     []orderBy{{Field: "name", Direction: desc}},
+			wantQuery:   "((models.name > ?) OR (models.name = ? AND models.model_id < ?))",
+			wantArgs:    []any{"apple", "apple", int64(3)},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			w := WrapperRequest[cursorModel]{OrderBy: each.orderBy, Cursor: &each.cursor}
+
+			gotQuery, gotArgs := w.keysetCondition(w.keyset())
+
+			assert.Equal(t, each.wantQuery, gotQuery)
+			assert.Equal(t, each.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	var (
+		items    = []cursorModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+		cursorOf = func(item cursorModel, backward bool) string {
+			return cursor{OrderBy: []string{"id ASC"}, Values: []any{item.ID}, Backward: backward}.String()
+		}
+	)
+
+	for _, each := range []struct {
+		description string
+		input       WrapperRequest[cursorModel]
+		items       []cursorModel
+		want        Page[cursorModel]
+	}{
+		{
+			description: "first page without more rows",
+			input:       WrapperRequest[cursorModel]{Limit: 3},
+			items:       items,
+			want:        Page[cursorModel]{Items: items, Limit: 3, Total: 3},
+		},
+		{
+			description: "first page with more rows",
+			input:       WrapperRequest[cursorModel]{Limit: 2},
+			items:       items,
+			want:        Page[cursorModel]{Items: items[:2], Limit: 2, Total: 3, HasMore: true, NextCursor: cursorOf(items[1], false)},
+		},
+		{
+			description: "page after a cursor without more rows",
+			input:       WrapperRequest[cursorModel]{Limit: 3, Cursor: &cursor{}},
+			items:       items[1:],
+			want:        Page[cursorModel]{Items: items[1:], Limit: 3, Total: 3, PrevCursor: cursorOf(items[1], true)},
+		},
+		{
+			description: "page before a backward cursor with more rows is reversed",
+			input:       WrapperRequest[cursorModel]{Limit: 1, Cursor: &cursor{Backward: true}},
+			items:       []cursorModel{items[1], items[0]},
+			want: Page[cursorModel]{
+				Items:      items[1:2],
+				Limit:      1,
+				Total:      3,
+				HasMore:    true,
+				NextCursor: cursorOf(items[1], false),
+				PrevCursor: cursorOf(items[1], true),
+			},
+		},
+		{
+			description: "empty page",
+			input:       WrapperRequest[cursorModel]{Limit: 3, Cursor: &cursor{}},
+			items:       nil,
+			want:        Page[cursorModel]{Items: Items[cursorModel]{}, Limit: 3, Total: 3},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got := each.input.NewPage(append([]cursorModel(nil), each.items...), int64(len(items)))
+
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// RestorePathName is appended to the path of a resource to restore it from the trash.
+	RestorePathName = "/restore"
+	// ActiveFirst orders the rows which have the same name, which can happen while they are in
+	// the trash, with the one which is not in the trash first and then the last deleted.
+	ActiveFirst = "deleted_at DESC NULLS FIRST"
+
+	cascadeQuery = "cascade"
+	dryRunQuery  = "dry_run"
+)
+
+var (
+	// ErrHasChildren is used when a resource can't be deleted because it still has children
+	// and the delete is not cascaded.
+	ErrHasChildren = errors.New("the resource has children, delete them first or use cascade=true")
+	// ErrNotInTrash is used when the resource to restore is not in the trash.
+	ErrNotInTrash = NewProblemError(NotFound, errors.New("the resource is not in the trash"))
+	// ErrNameReused is used when a resource can't be restored because its name was taken by
+	// another resource while it was in the trash.
+	ErrNameReused = errors.New("the name was taken while the resource was in the trash")
+)
+
+// DeleteOptions are the options of the DELETE requests.
+type DeleteOptions struct {
+	// Cascade deletes the children of the resource too. Otherwise, the resource can't be
+	// deleted while it has children.
+	Cascade bool
+	// DryRun doesn't delete anything, it only returns the SQL and the rows that would be deleted.
+	DryRun bool
+}
+
+// ParseDeleteOptions parses the cascade and dry_run query values, which are false by default.
+func ParseDeleteOptions(q QueryParser) (DeleteOptions, error) {
+	var (
+		opts DeleteOptions
+		err  error
+	)
+
+	if opts.Cascade, err = parseBoolQuery(q, cascadeQuery); err != nil {
+		return opts, err
+	}
+
+	opts.DryRun, err = parseBoolQuery(q, dryRunQuery)
+
+	return opts, err
+}
+
+func parseBoolQuery(q QueryParser, key string) (bool, error) {
+	value := q.Query(key)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, NewProblemError(BadRequest, fmt.Errorf("%s query must be true or false, got %q", key, value))
+	}
+
+	return b, nil
+}
+
+// Level is each of the levels of a hierarchy of resources. The rows of a level reference
+// the rows of the previous level through the key of the previous level.
+type Level struct {
+	Kind  string
+	Table string
+	Key   string
+}
+
+// Node is each of the resources affected by a delete or a restore.
+type Node struct {
+	ID        int        `gorm:"column:id" json:"-" xml:"-"`
+	Kind      string     `gorm:"-" json:"kind" xml:"kind,attr"`
+	Name      string     `gorm:"column:name" json:"name" xml:",chardata"`
+	DeletedAt *time.Time `gorm:"column:deleted_at" json:"deleted_at,omitempty" xml:"deleted_at,attr,omitempty"`
+}
+
+// DeleteResult is the response of the DELETE requests. It contains the resources deleted, or
+// the ones that would be deleted along with the SQL statements when it is a dry run.
+type DeleteResult struct {
+	XMLName xml.Name `json:"-" xml:"Delete"`
+	DryRun  bool     `json:"dry_run" xml:"DryRun"`
+	Cascade bool     `json:"cascade" xml:"Cascade"`
+	Rows    int64    `json:"rows" xml:"Rows"`
+	Nodes   []Node   `json:"nodes" xml:"Nodes>Node"`
+	SQL     []string `json:"sql,omitempty" xml:"SQL>Statement,omitempty"`
+}
+
+// RestoreResult is the response of the restore requests. It contains the resources restored.
+type RestoreResult struct {
+	XMLName xml.Name `json:"-" xml:"Restore"`
+	Rows    int64    `json:"rows" xml:"Rows"`
+	Nodes   []Node   `json:"nodes" xml:"Nodes>Node"`
+}
+
+// TrashCondition returns the condition which matches the rows of table that are in the trash
+// if deleted is true, or the ones that are not otherwise.
+func TrashCondition(table string, deleted bool) string {
+	if deleted {
+		return table + ".deleted_at IS NOT NULL"
+	}
+
+	return table + ".deleted_at IS NULL"
+}
+
+// DeleteTree soft deletes roots, which are rows of the first level, along with all their
+// descendants when the delete is cascaded. Otherwise, it fails with a conflict listing the
+// children of roots. All the rows get the same deletion time, so they can be restored together.
+// The descendants are read with tx and, when it is a dry run, the statements are built with dry
+// instead of being executed.
+func DeleteTree(tx, dry *gorm.DB, levels []Level, roots []Node, opts DeleteOptions) (DeleteResult, error) {
+	var (
+		result = DeleteResult{DryRun: opts.DryRun, Cascade: opts.Cascade}
+		tree   = [][]Node{withKind(roots, levels[0].Kind)}
+		now    = tx.NowFunc()
+	)
+
+	for i := 1; i < len(levels) && len(tree[i-1]) > 0; i++ {
+		var children []Node
+
+		txx := tx.Table(levels[i].Table).
+			Select(levels[i].Key+" AS id", "name").
+			Where(levels[i-1].Key+" IN ?", nodeIDs(tree[i-1])).
+			Where("deleted_at IS NULL").
+			Order(levels[i].Key).
+			Find(&children)
+		if txx.Error != nil {
+			return result, txx.Error
+		}
+
+		children = withKind(children, levels[i].Kind)
+		if !opts.Cascade && len(children) > 0 {
+			err := NewProblemError(Conflict, ErrHasChildren)
+			err.Children = children
+			return result, err
+		}
+
+		tree = append(tree, children)
+	}
+
+	for i := len(tree) - 1; i >= 0; i-- {
+		if len(tree[i]) == 0 {
+			continue
+		}
+
+		query := "UPDATE " + levels[i].Table + " SET deleted_at = ? WHERE " + levels[i].Key + " IN ?"
+
+		if opts.DryRun {
+			stmt := dry.Exec(query, now, nodeIDs(tree[i])).Statement
+			result.SQL = append(result.SQL, dry.Dialector.Explain(stmt.SQL.String(), stmt.Vars...))
+			result.Rows += int64(len(tree[i]))
+		} else if txx := tx.Exec(query, now, nodeIDs(tree[i])); txx.Error != nil {
+			return result, txx.Error
+		} else {
+			result.Rows += txx.RowsAffected
+			withDeletedAt(tree[i], &now)
+		}
+
+		result.Nodes = append(result.Nodes, tree[i]...)
+	}
+
+	return result, nil
+}
+
+// RestoreTree restores roots, which are soft deleted rows of the first level, along with the
+// descendants which were deleted at the same time, that is, by the same cascaded delete. It
+// fails with a Conflict if the name of any of them was taken by a resource not in the trash.
+func RestoreTree(tx *gorm.DB, levels []Level, roots []Node) (RestoreResult, error) {
+	var (
+		result    RestoreResult
+		tree      = [][]Node{withKind(roots, levels[0].Kind)}
+		deletedAt = make([]time.Time, 0, len(roots))
+	)
+
+	for _, each := range roots {
+		if each.DeletedAt != nil {
+			deletedAt = append(deletedAt, *each.DeletedAt)
+		}
+	}
+
+	for i := 1; i < len(levels) && len(tree[i-1]) > 0; i++ {
+		var children []Node
+
+		txx := tx.Table(levels[i].Table).
+			Select(levels[i].Key+" AS id", "name", "deleted_at").
+			Where(levels[i-1].Key+" IN ?", nodeIDs(tree[i-1])).
+			Where("deleted_at IN ?", deletedAt).
+			Order(levels[i].Key).
+			Find(&children)
+		if txx.Error != nil {
+			return result, txx.Error
+		}
+
+		tree = append(tree, withKind(children, levels[i].Kind))
+	}
+
+	for i := range tree {
+		if len(tree[i]) == 0 {
+			continue
+		}
+
+		var taken []string
+
+		txx := tx.Table(levels[i].Table).Where("deleted_at IS NULL AND name IN ?", nodeNames(tree[i])).Order("name").Pluck("name", &taken)
+		if txx.Error != nil {
+			return result, txx.Error
+		} else if len(taken) > 0 {
+			return result, NewProblemError(Conflict, fmt.Errorf("%w: %s %s", ErrNameReused, levels[i].Kind, strings.Join(taken, ", ")))
+		}
+
+		txx = tx.Exec("UPDATE "+levels[i].Table+" SET deleted_at = NULL WHERE "+levels[i].Key+" IN ?", nodeIDs(tree[i]))
+		if txx.Error != nil {
+			return result, txx.Error
+		}
+
+		result.Rows += txx.RowsAffected
+		result.Nodes = append(result.Nodes, withDeletedAt(tree[i], nil)...)
+	}
+
+	return result, nil
+}
+
+// PurgeTree hard deletes the rows of all the levels which were soft deleted before the time
+// passed, starting from the last level so the children are deleted before their parents.
+// It returns the amount of rows deleted of each kind.
+func PurgeTree(tx *gorm.DB, levels []Level, before time.Time) (map[string]int64, error) {
+	var rows = make(map[string]int64, len(levels))
+
+	for i := len(levels) - 1; i >= 0; i-- {
+		txx := tx.Exec("DELETE FROM "+levels[i].Table+" WHERE deleted_at < ?", before)
+		if txx.Error != nil {
+			return rows, txx.Error
+		}
+
+		rows[levels[i].Kind] = txx.RowsAffected
+	}
+
+	return rows, nil
+}
+
+func withKind(nodes []Node, kind string) []Node {
+	for i := range nodes {
+		nodes[i].Kind = kind
+	}
+
+	return nodes
+}
+
+func withDeletedAt(nodes []Node, deletedAt *time.Time) []Node {
+	for i := range nodes {
+		nodes[i].DeletedAt = deletedAt
+	}
+
+	return nodes
+}
+
+func nodeNames(nodes []Node) []string {
+	names := make([]string, 0, len(nodes))
+	for _, each := range nodes {
+		names = append(names, each.Name)
+	}
+
+	return names
+}
+
+func nodeIDs(nodes []Node) []int {
+	ids := make([]int, 0, len(nodes))
+	for _, each := range nodes {
+		ids = append(ids, each.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type queryValues url.Values
+
+func (q queryValues) Query(key string) string {
+	return url.Values(q).Get(key)
+}
+
+func (q queryValues) DefaultQuery(key, defaultValue string) string {
+	if value := url.Values(q).Get(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+func (q queryValues) QueryArray(key string) []string {
+	return q[key]
+}
+
+func TestParseDeleteOptions(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       queryValues
+		want        DeleteOptions
+		wantErr     bool
+	}{
+		{
+			description: "restrict by default",
+			input:       queryValues{},
+		},
+		{
+			description: "cascade dry run",
+			input:       queryValues{"cascade": {"true"}, "dry_run": {"1"}},
+			want:        DeleteOptions{Cascade: true, DryRun: true},
+		},
+		{
+			description: "invalid cascade",
+			input:       queryValues{"cascade": {"yes"}},
+			wantErr:     true,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := ParseDeleteOptions(each.input)
+			if each.wantErr {
+				assert.Equal(t, BadRequest, problemTypeOf(err, 0))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestTrashCondition(t *testing.T) {
+	assert.Equal(t, "parents.deleted_at IS NOT NULL", TrashCondition("parents", true))
+	assert.Equal(t, "parents.deleted_at IS NULL", TrashCondition("parents", false))
+}
+
+func TestDeleteTreeDryRun(t *testing.T) {
+	now := time.Date(2022, time.September, 1, 10, 0, 0, 0, time.UTC)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		NowFunc:                func() time.Time { return now },
+	})
+	assert.NoError(t, err)
+
+	levels := []Level{
+		{Kind: "parent", Table: "parents", Key: "parent_id"},
+		{Kind: "child", Table: "children", Key: "child_id"},
+	}
+
+	got, err := DeleteTree(db, db, levels, []Node{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}, DeleteOptions{DryRun: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, DeleteResult{
+		DryRun: true,
+		Rows:   2,
+		Nodes:  []Node{{ID: 1, Kind: "parent", Name: "a"}, {ID: 2, Kind: "parent", Name: "b"}},
+		SQL:    []string{"UPDATE parents SET deleted_at = '2022-09-01 10:00:00' WHERE parent_id IN (1,2)"},
+	}, got)
+}
+
+func TestRestoreTreeDryRun(t *testing.T) {
+	deletedAt := time.Date(2022, time.September, 1, 10, 0, 0, 0, time.UTC)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	assert.NoError(t, err)
+
+	var queries []string
+	record := func(tx *gorm.DB) {
+		queries = append(queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:query", record))
+	assert.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:raw", record))
+
+	levels := []Level{{Kind: "parent", Table: "parents", Key: "parent_id"}}
+
+	_, err = RestoreTree(db, levels, []Node{{ID: 1, Name: "a", DeletedAt: &deletedAt}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`SELECT "name" FROM "parents" WHERE deleted_at IS NULL AND name IN ('a') ORDER BY name`,
+		"UPDATE parents SET deleted_at = NULL WHERE parent_id IN (1)",
+	}, queries, "the names are checked before restoring")
+}
@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	filterQuery = "filter"
+
+	// filterSeparator separates the field, the operator and the value of a filter.
+	filterSeparator = ":"
+	// filterValuesSeparator separates the values of the in operator.
+	filterValuesSeparator = ","
+)
+
+// ErrInvalidFilter is used when a filter query is malformed, uses an unknown operator or a field
+// which can't be filtered by, or its value doesn't match the type of the field.
+var ErrInvalidFilter = errors.New("invalid filter query")
+
+// operators are the operators allowed, in the order they are listed in the errors.
+var operators = []Operator{eq, ne, lt, lte, gt, gte, prefix, suffix, contains, in}
+
+// FilterAllower is implemented by the models which can be filtered. The keys are the fields
+// allowed to filter by and, if the value is a string, it is the column of the field.
+type FilterAllower interface {
+	FilterColumnsAllowed() map[string]any
+}
+
+// Operator is the comparison applied by a filter between a field and a value.
+type Operator string
+
+const (
+	eq       Operator = "eq"
+	ne       Operator = "ne"
+	lt       Operator = "lt"
+	lte      Operator = "lte"
+	gt       Operator = "gt"
+	gte      Operator = "gte"
+	prefix   Operator = "prefix"
+	suffix   Operator = "suffix"
+	contains Operator = "contains"
+	in       Operator = "in"
+)
+
+// Set is used to set the value to an operator, returning ErrInvalidFilter
+// if the operator is unknown.
+func (o *Operator) Set(input string) error {
+	switch op := Operator(strings.ToLower(input)); op {
+	case eq, ne, lt, lte, gt, gte, prefix, suffix, contains, in:
+		*o = op
+	default:
+		return fmt.Errorf("%w: operator must be one of %v, got %q", ErrInvalidFilter, operators, input)
+	}
+	return nil
+}
+
+// String returns the string representation of the operator.
+func (o Operator) String() string {
+	return string(o)
+}
+
+// filter is each of the filters passed with the query param filter, using the syntax
+// field:operator:value. For example name:prefix:app or id:in:1,2,3
+type filter struct {
+	Field    string
+	Operator Operator
+	Value    string
+}
+
+// parseArrFilter parses the filters of the request for body. It fails with a BadRequest problem
+// naming the first filter which is malformed, uses an unknown operator or a field not allowed by
+// body, or whose value doesn't match the type of the field.
+func parseArrFilter(arr []string, body any) ([]filter, error) {
+	var (
+		result []filter
+		cols   map[string]any
+	)
+
+	if fa, ok := body.(FilterAllower); ok {
+		cols = fa.FilterColumnsAllowed()
+	}
+
+	for _, each := range arr {
+		f, err := parseFilter(each)
+		if err == nil {
+			err = f.check(body, cols)
+		}
+
+		if err != nil {
+			return nil, NewProblemError(BadRequest, fmt.Errorf("%s %q: %w", filterQuery, each, err))
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+func parseFilter(input string) (filter, error) {
+	var (
+		splitted = strings.SplitN(input, filterSeparator, 3)
+
+		op Operator
+	)
+
+	if len(splitted) != 3 || splitted[0] == "" || splitted[2] == "" {
+		return filter{}, fmt.Errorf("%w: it must be field:operator:value", ErrInvalidFilter)
+	}
+
+	if err := op.Set(splitted[1]); err != nil {
+		return filter{}, err
+	}
+
+	return filter{
+		Field:    splitted[0],
+		Operator: op,
+		Value:    splitted[2],
+	}, nil
+}
+
+// check returns an error if the field of the filter is not one of cols, the fields allowed by
+// body, or if its value doesn't match the type of the field in body.
+func (f filter) check(body any, cols map[string]any) error {
+	if len(cols) == 0 {
+		return fmt.Errorf("%w: the resource can't be filtered", ErrInvalidFilter)
+	}
+
+	if _, ok := cols[f.Field]; !ok {
+		allowed := make([]string, 0, len(cols))
+		for each := range cols {
+			allowed = append(allowed, each)
+		}
+
+		sort.Strings(allowed)
+
+		return fmt.Errorf("%w: field must be one of %v, got %q", ErrInvalidFilter, allowed, f.Field)
+	}
+
+	s, err := schema.Parse(body, &schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil
+	}
+
+	field := s.LookUpField(columnOf(cols, f.Field))
+	if field == nil {
+		return nil
+	}
+
+	t := field.FieldType
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.String && (f.Operator == prefix || f.Operator == suffix || f.Operator == contains) {
+		return fmt.Errorf("%w: operator %s only applies to text fields", ErrInvalidFilter, f.Operator)
+	}
+
+	values := []string{f.Value}
+	if f.Operator == in {
+		values = strings.Split(f.Value, filterValuesSeparator)
+	}
+
+	for _, each := range values {
+		if err := checkValue(t, each); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidFilter, err)
+		}
+	}
+
+	return nil
+}
+
+// checkValue returns an error if value can't be converted to the type t.
+func checkValue(t reflect.Type, value string) error {
+	var err error
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, err = strconv.ParseInt(value, 10, t.Bits()); err != nil {
+			return fmt.Errorf("value must be an integer, got %q", value)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err = strconv.ParseUint(value, 10, t.Bits()); err != nil {
+			return fmt.Errorf("value must be a positive integer, got %q", value)
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, err = strconv.ParseFloat(value, t.Bits()); err != nil {
+			return fmt.Errorf("value must be a number, got %q", value)
+		}
+	case reflect.Bool:
+		if _, err = strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("value must be true or false, got %q", value)
+		}
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			if _, err = time.Parse(time.RFC3339, value); err != nil {
+				return fmt.Errorf("value must be a RFC 3339 time, got %q", value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// clause returns the parameterised condition of the filter applied to column. The prefix,
+// suffix and contains operators are case insensitive.
+func (f filter) clause(column string) (string, []any) {
+	switch f.Operator {
+	case ne:
+		return column + " <> ?", []any{f.Value}
+	case lt:
+		return column + " < ?", []any{f.Value}
+	case lte:
+		return column + " <= ?", []any{f.Value}
+	case gt:
+		return column + " > ?", []any{f.Value}
+	case gte:
+		return column + " >= ?", []any{f.Value}
+	case prefix:
+		return column + " ILIKE ?", []any{escapeLike(f.Value) + "%"}
+	case suffix:
+		return column + " ILIKE ?", []any{"%" + escapeLike(f.Value)}
+	case contains:
+		return column + " ILIKE ?", []any{"%" + escapeLike(f.Value) + "%"}
+	case in:
+		return column + " IN ?", []any{strings.Split(f.Value, filterValuesSeparator)}
+	default:
+		return column + " = ?", []any{f.Value}
+	}
+}
+
+// escapeLike escapes the wildcards of the LIKE patterns, so they are matched literally.
+func escapeLike(input string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(input)
+}
+
+// ToFilter applies the filters of the request to db, skipping the ones whose field is not
+// allowed by the model, which ParseRequest already rejects.
+func (w WrapperRequest[T]) ToFilter(db *gorm.DB) *gorm.DB {
+	fa, ok := any(w.Body).(FilterAllower)
+	if !ok {
+		return db
+	}
+
+	var cols = fa.FilterColumnsAllowed()
+
+	for _, each := range w.Filters {
+		if _, ok := cols[each.Field]; ok {
+			query, args := each.clause(w.qualify(columnOf(cols, each.Field)))
+			db = db.Where(query, args...)
+		}
+	}
+
+	return db
+}
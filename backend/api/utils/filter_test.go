@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filterModel is a model with a field of each type which can be filtered by.
+type filterModel struct {
+	ID        int       `gorm:"column:model_id"`
+	Name      string    `gorm:"column:name"`
+	Price     float64   `gorm:"column:price"`
+	Available bool      `gorm:"column:available"`
+	Stock     *uint     `gorm:"column:stock"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (filterModel) FilterColumnsAllowed() map[string]any {
+	return map[string]any{"id": "model_id", "name": "name", "price": "price", "available": "available", "stock": "stock", "updated_at": "updated_at"}
+}
+
+func TestParseFilter(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       []string
+		want        []filter
+	}{
+		{
+			description: "no value passed",
+			input:       []string{},
+		},
+		{
+			description: "all values are correct",
+			input: []string{
+				"name:prefix:app", "id:in:1,2,3", "price:lte:2.5", "available:eq:true", "stock:gt:0", "updated_at:gte:2022-10-01T00:00:00Z",
+			},
+			want: []filter{
+				{Field: "name", Operator: prefix, Value: "app"},
+				{Field: "id", Operator: in, Value: "1,2,3"},
+				{Field: "price", Operator: lte, Value: "2.5"},
+				{Field: "available", Operator: eq, Value: "true"},
+				{Field: "stock", Operator: gt, Value: "0"},
+				{Field: "updated_at", Operator: gte, Value: "2022-10-01T00:00:00Z"},
+			},
+		},
+		{
+			description: "operator is case insensitive and value can contain the separator",
+			input:       []string{"name:EQ:a:b"},
+			want:        []filter{{Field: "name", Operator: eq, Value: "a:b"}},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := parseArrFilter(each.input, filterModel{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, each := range []struct {
+		input, wantDetail string
+		body              any
+	}{
+		{input: "name:app", wantDetail: "it must be field:operator:value"},
+		{input: ":eq:app", wantDetail: "it must be field:operator:value"},
+		{input: "name:eq:", wantDetail: "it must be field:operator:value"},
+		{input: "name:like:app", wantDetail: `operator must be one of [eq ne lt lte gt gte prefix suffix contains in], got "like"`},
+		{input: "colour:eq:red", wantDetail: `field must be one of [available id name price stock updated_at], got "colour"`},
+		{input: "id:gt:abc", wantDetail: `value must be an integer, got "abc"`},
+		{input: "id:in:1,two", wantDetail: `value must be an integer, got "two"`},
+		{input: "id:contains:1", wantDetail: "operator contains only applies to text fields"},
+		{input: "price:eq:cheap", wantDetail: `value must be a number, got "cheap"`},
+		{input: "available:eq:maybe", wantDetail: `value must be true or false, got "maybe"`},
+		{input: "stock:gt:-1", wantDetail: `value must be a positive integer, got "-1"`},
+		{input: "updated_at:lt:yesterday", wantDetail: `value must be a RFC 3339 time, got "yesterday"`},
+		{input: "name:eq:app", body: orderByAllower{}, wantDetail: "the resource can't be filtered"},
+	} {
+		t.Run(each.input, func(t *testing.T) {
+			var pErr *ProblemError
+
+			body := each.body
+			if body == nil {
+				body = filterModel{}
+			}
+
+			_, err := parseArrFilter([]string{"name:eq:app", each.input}, body)
+
+			require.ErrorAs(t, err, &pErr)
+			assert.Equal(t, BadRequest, pErr.Type)
+			assert.ErrorIs(t, err, ErrInvalidFilter)
+			assert.Contains(t, err.Error(), fmt.Sprintf("filter %q", each.input))
+			assert.Contains(t, err.Error(), each.wantDetail)
+		})
+	}
+}
+
+func TestFilterClause(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       filter
+		wantQuery   string
+		wantArgs    []any
+	}{
+		{
+			description: "equal operator",
+			input:       filter{Field: "name", Operator: eq, Value: "apple"},
+			wantQuery:   "t.name = ?",
+			wantArgs:    []any{"apple"},
+		},
+		{
+			description: "not equal operator",
+			input:       filter{Field: "name", Operator: ne, Value: "apple"},
+			wantQuery:   "t.name <> ?",
+			wantArgs:    []any{"apple"},
+		},
+		{
+			description: "lower than or equal operator",
+			input:       filter{Field: "name", Operator: lte, Value: "b"},
+			wantQuery:   "t.name <= ?",
+			wantArgs:    []any{"b"},
+		},
+		{
+			description: "prefix operator escapes wildcards",
+			input:       filter{Field: "name", Operator: prefix, Value: "100%_"},
+			wantQuery:   "t.name ILIKE ?",
+			wantArgs:    []any{`100\%\_%`},
+		},
+		{
+			description: "suffix operator",
+			input:       filter{Field: "name", Operator: suffix, Value: "ple"},
+			wantQuery:   "t.name ILIKE ?",
+			wantArgs:    []any{"%ple"},
+		},
+		{
+			description: "contains operator",
+			input:       filter{Field: "description", Operator: contains, Value: "juicy"},
+			wantQuery:   "t.name ILIKE ?",
+			wantArgs:    []any{"%juicy%"},
+		},
+		{
+			description: "in operator",
+			input:       filter{Field: "id", Operator: in, Value: "1,2,3"},
+			wantQuery:   "t.name IN ?",
+			wantArgs:    []any{[]string{"1", "2", "3"}},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			gotQuery, gotArgs := each.input.clause("t.name")
+
+			assert.Equal(t, each.wantQuery, gotQuery)
+			assert.Equal(t, each.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestOperator(t *testing.T) {
+	t.Run("set of operator should return error when incorrect value is passed", func(t *testing.T) {
+		var o Operator
+
+		assert.ErrorIs(t, o.Set("like"), ErrInvalidFilter)
+		assert.Equal(t, Operator(""), o)
+	})
+
+	t.Run("set of operator should set the correct one when input is correct", func(t *testing.T) {
+		var o Operator
+
+		assert.Nil(t, o.Set("Contains"))
+		assert.Equal(t, contains, o)
+		assert.Equal(t, "contains", o.String())
+	})
+}
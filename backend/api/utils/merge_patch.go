@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MIMEMergePatchJSON is the Content-Type of a JSON Merge Patch document (RFC 7396).
+const MIMEMergePatchJSON = "application/merge-patch+json"
+
+// ErrInvalidPatch is used when the JSON Merge Patch document is not valid JSON or it doesn't
+// patch the members of the resource.
+var ErrInvalidPatch = errors.New("the patch must be a JSON object with the members of the resource")
+
+// MergePatch applies the JSON Merge Patch document patch to the JSON document doc,
+// following the algorithm described in RFC 7396.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, p any
+
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatch(target, p))
+}
+
+// ApplyMergePatch marshals src as JSON, patches it and returns the result unmarshalled
+// into a new value of the same type. It fails with a BadRequest problem if the patch is not
+// valid JSON nor an object, and with a ValidationFailed one if a member has another type.
+func ApplyMergePatch[T any](src T, patch []byte) (T, error) {
+	var result T
+
+	doc, err := json.Marshal(src)
+	if err != nil {
+		return result, err
+	}
+
+	if doc, err = MergePatch(doc, patch); err != nil {
+		return result, patchErr(err)
+	}
+
+	if err = json.Unmarshal(doc, &result); err != nil {
+		return result, patchErr(err)
+	}
+
+	return result, nil
+}
+
+// patchErr wraps the errors of applying a patch into the problem of the request.
+func patchErr(err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return NewProblemError(BadRequest, fmt.Errorf("%w: %s", ErrInvalidPatch, syntaxErr))
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return NewProblemError(ValidationFailed, ErrValidation, FieldError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("%s must be a %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value),
+		})
+	}
+
+	return NewProblemError(BadRequest, fmt.Errorf("%w: %s", ErrInvalidPatch, err))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+
+	return t
+}
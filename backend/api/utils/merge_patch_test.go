@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Test cases are the ones listed in the appendix A of the RFC 7396.
+	for _, each := range []struct {
+		description, doc, patch, want string
+	}{
+		{description: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{description: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{description: "remove member", doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{description: "remove one of many members", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{description: "array replaced by string", doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{description: "string replaced by array", doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{description: "nested objects", doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{description: "array of objects replaced", doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{description: "arrays are not merged", doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{description: "object replaced by array", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{description: "object replaced by null", doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{description: "object replaced by string", doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{description: "null members are kept in the patch", doc: `{"e":null}`, patch: `{"a":1}`, want: `{"a":1,"e":null}`},
+		{description: "array replaced by object", doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{description: "nested null removes nested member", doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+		{description: "empty document", doc: ``, patch: `{"a":"b"}`, want: `{"a":"b"}`},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := MergePatch([]byte(each.doc), []byte(each.patch))
+
+			assert.Nil(t, err)
+			assert.JSONEq(t, each.want, string(got))
+		})
+	}
+
+	t.Run("invalid patch returns error", func(t *testing.T) {
+		_, err := MergePatch([]byte(`{"a":"b"}`), []byte(`{"a":`))
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	type resource struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	for _, each := range []struct {
+		description, patch string
+		src, want          resource
+	}{
+		{
+			description: "only the name is changed",
+			src:         resource{Name: "fruit", Description: "sweet and fleshy"},
+			patch:       `{"name":"fruits"}`,
+			want:        resource{Name: "fruits", Description: "sweet and fleshy"},
+		},
+		{
+			description: "description is removed",
+			src:         resource{Name: "fruit", Description: "sweet and fleshy"},
+			patch:       `{"description":null}`,
+			want:        resource{Name: "fruit"},
+		},
+		{
+			description: "unknown members are ignored",
+			src:         resource{Name: "fruit", Description: "sweet and fleshy"},
+			patch:       `{"unknown":"value"}`,
+			want:        resource{Name: "fruit", Description: "sweet and fleshy"},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := ApplyMergePatch(each.src, []byte(each.patch))
+
+			assert.Nil(t, err)
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestApplyMergePatchErrors(t *testing.T) {
+	type resource struct {
+		Name string `json:"name"`
+	}
+
+	for _, each := range []struct {
+		description, patch string
+		wantType           ProblemType
+		wantFields         []FieldError
+	}{
+		{
+			description: "malformed json",
+			patch:       `{"name":`,
+			wantType:    BadRequest,
+		},
+		{
+			description: "array instead of object",
+			patch:       `[1]`,
+			wantType:    BadRequest,
+		},
+		{
+			description: "member of another type",
+			patch:       `{"name":5}`,
+			wantType:    ValidationFailed,
+			wantFields:  []FieldError{{Field: "name", Message: "name must be a string, got number"}},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var pErr *ProblemError
+
+			_, err := ApplyMergePatch(resource{Name: "fruits"}, []byte(each.patch))
+
+			assert.ErrorAs(t, err, &pErr)
+			assert.Equal(t, each.wantType, pErr.Type)
+			assert.Equal(t, each.wantFields, pErr.Fields)
+		})
+	}
+}
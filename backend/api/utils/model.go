@@ -3,24 +3,37 @@ package utils
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"math"
+	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 var (
 	errInvalidDirection = errors.New("invalid direction for the value supplied")
 	errInvalidOrderBy   = errors.New("invalid order by for the value supplied")
+
+	// ErrInvalidLimits is used when the default limit of the pages is bigger than the maximum one.
+	ErrInvalidLimits = errors.New("default limit must be between 1 and the max limit")
+
+	// limits are the default and the maximum limit of the pages, which can be configured.
+	limits atomic.Pointer[[2]int]
 )
 
 const (
 	limitQuery   = "limit"
 	skipQuery    = "skip"
 	orderByQuery = "order_by"
+	cursorQuery  = "cursor"
 
-	limitDefault = 50
-	limitMax     = 100
-	limitMin     = 1
+	// LimitDefault is the size of the pages when the request doesn't set the limit, unless it is configured.
+	LimitDefault = 50
+	// LimitMax is the maximum limit of the pages, unless it is configured.
+	LimitMax = 100
+	limitMin = 1
 
 	skipDefault = 0
 	skipMax     = math.MaxInt32
@@ -74,6 +87,14 @@ func (d *Direction) unmarshalText(src string) bool {
 	return true
 }
 
+// reverse returns the opposite direction.
+func (d Direction) reverse() Direction {
+	if d == desc {
+		return asc
+	}
+	return desc
+}
+
 // String returns the string representation of the type direction
 func (d Direction) String() string {
 	var result string
@@ -92,6 +113,8 @@ type WrapperRequest[T OrderByAllower] struct {
 	Limit   int
 	Skip    int
 	OrderBy []orderBy
+	Filters []filter
+	Cursor  *cursor
 	Body    T
 }
 
@@ -104,19 +127,234 @@ func (o orderBy) String() string {
 	return o.Field + " " + o.Direction.String()
 }
 
-type WrapperResponse struct {
-	XMLName xml.Name `json:"-" xml:"Response"`
-	Code    int      `json:"code" xml:"Code"`
-	Msg     string   `json:"message" xml:"Message"`
+// Page is the list of resources returned by the list handlers, along with the
+// pagination values of the request, the total amount of resources and the
+// cursors needed to request the next and the previous pages.
+type Page[T any] struct {
+	XMLName    xml.Name `json:"-" xml:"Page"`
+	Items      Items[T] `json:"items" xml:"Items"`
+	Limit      int      `json:"limit" xml:"Limit"`
+	Skip       int      `json:"skip" xml:"Skip"`
+	Total      int64    `json:"total" xml:"Total"`
+	HasMore    bool     `json:"has_more" xml:"HasMore"`
+	NextCursor string   `json:"next_cursor,omitempty" xml:"NextCursor,omitempty"`
+	PrevCursor string   `json:"prev_cursor,omitempty" xml:"PrevCursor,omitempty"`
+}
+
+// Items is a list of resources which is wrapped by an Items element when it is
+// marshalled to XML, keeping the element name of each resource.
+type Items[T any] []T
+
+// MarshalXML marshals each item inside of the start element.
+func (i Items[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct{ Values []T }{i}, start)
+}
+
+// CheckLimits returns the default and the maximum limit of the pages, being LimitDefault and
+// LimitMax the zero ones, or an error if they are not valid.
+func CheckLimits(limitDefault, limitMax int) (int, int, error) {
+	if limitDefault == 0 {
+		limitDefault = LimitDefault
+	}
+
+	if limitMax == 0 {
+		limitMax = LimitMax
+	}
+
+	if limitDefault < limitMin || limitMax < limitDefault {
+		return 0, 0, fmt.Errorf("%w, got %d and %d", ErrInvalidLimits, limitDefault, limitMax)
+	}
+
+	return limitDefault, limitMax, nil
+}
+
+// SetLimits changes the default and the maximum limit of the pages, as in CheckLimits. It is safe
+// to call it while the requests are being parsed.
+func SetLimits(limitDefault, limitMax int) error {
+	limitDefault, limitMax, err := CheckLimits(limitDefault, limitMax)
+	if err != nil {
+		return err
+	}
+
+	limits.Store(&[2]int{limitDefault, limitMax})
+
+	return nil
+}
+
+func currentLimits() (limitDefault, limitMax int) {
+	if l := limits.Load(); l != nil {
+		return l[0], l[1]
+	}
+
+	return LimitDefault, LimitMax
 }
 
-func ParseRequest[T OrderByAllower](qParser QueryParser, body T) WrapperRequest[T] {
-	return WrapperRequest[T]{
+// ParseRequest returns the pagination, the ordering and the filters of the request along with
+// its body. It fails with a BadRequest problem if a filter or the cursor is not valid.
+func ParseRequest[T OrderByAllower](qParser QueryParser, body T) (WrapperRequest[T], error) {
+	limitDefault, limitMax := currentLimits()
+
+	w := WrapperRequest[T]{
 		Limit:   ParseNumber(qParser.Query(limitQuery), limitDefault, Boundaries(limitMin, limitMax)),
 		Skip:    ParseNumber(qParser.Query(skipQuery), skipDefault, Boundaries(skipMin, skipMax)),
 		OrderBy: parseArrOrderBy(qParser.QueryArray(orderByQuery)),
 		Body:    body,
 	}
+
+	filters, err := parseArrFilter(qParser.QueryArray(filterQuery), body)
+	if err != nil {
+		return w, err
+	}
+
+	w.Filters = filters
+
+	if input := qParser.Query(cursorQuery); input != "" {
+		c, err := parseCursor(input, w.keyset())
+		if err != nil {
+			return w, NewProblemError(BadRequest, err)
+		}
+
+		w.Cursor = c
+	}
+
+	return w, nil
+}
+
+// ParseValues returns the values of the query key, failing with a BadRequest problem if any of
+// them is not one of allowed.
+func ParseValues(q QueryParser, key string, allowed []string) ([]string, error) {
+	values := q.QueryArray(key)
+	for _, each := range values {
+		if !Contains(allowed, each) {
+			return nil, NewProblemError(BadRequest, fmt.Errorf("%s query must be one of %v, got %q", key, allowed, each))
+		}
+	}
+
+	return values, nil
+}
+
+// Contains returns whether value is one of the elements of arr.
+func Contains[T comparable](arr []T, value T) bool {
+	for i := range arr {
+		if arr[i] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Counted is implemented by the rows of the queries which count the rows without pagination
+// along with the page, using count(*) OVER ().
+type Counted interface {
+	// RowsTotal returns the amount of rows without pagination.
+	RowsTotal() int64
+}
+
+// NewCountedPage returns the page of items paginated by the limit and the skip of w, whose
+// total is the one counted by the rows.
+func NewCountedPage[T Counted, Q OrderByAllower](w WrapperRequest[Q], items []T) Page[T] {
+	var page = Page[T]{
+		Items: Items[T]{},
+		Limit: w.Limit,
+		Skip:  w.Skip,
+	}
+
+	if len(items) > 0 {
+		page.Items = items
+		page.Total = items[0].RowsTotal()
+		page.HasMore = int64(w.Skip+len(items)) < page.Total
+	}
+
+	return page
+}
+
+// NewPage returns the page of items, which must be the result of a query scoped with ToScope,
+// being total the amount of rows without pagination. The extra row fetched by ToScope is used
+// to know if there are more rows after the page, or before it when the page was requested
+// with a backward cursor.
+func (w WrapperRequest[T]) NewPage(items []T, total int64) Page[T] {
+	var (
+		backward = w.Cursor != nil && w.Cursor.Backward
+		more     = len(items) > w.Limit
+		page     = Page[T]{Items: Items[T]{}, Limit: w.Limit, Total: total}
+	)
+
+	if w.Cursor == nil {
+		page.Skip = w.Skip
+	}
+
+	if more {
+		items = items[:w.Limit]
+	}
+
+	if len(items) == 0 {
+		return page
+	}
+
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page.Items, page.HasMore = items, more || backward
+
+	if more || backward {
+		if c := w.newCursor(items[len(items)-1], false); c != nil {
+			page.NextCursor = c.String()
+		}
+	}
+
+	if (more && backward) || (!backward && (w.Cursor != nil || w.Skip > 0)) {
+		if c := w.newCursor(items[0], true); c != nil {
+			page.PrevCursor = c.String()
+		}
+	}
+
+	return page
+}
+
+// Links returns the value of the Link header (RFC 8288) of the page, with the first, prev,
+// next and last pages. The links keep the query parameters of u, the URL of the request.
+func (p Page[T]) Links(u url.URL) string {
+	var links []string
+
+	link := func(rel string, set func(url.Values)) {
+		q := u.Query()
+		q.Del(cursorQuery)
+		q.Del(skipQuery)
+		q.Set(limitQuery, strconv.Itoa(p.Limit))
+		set(q)
+
+		u.RawQuery = q.Encode()
+		links = append(links, "<"+u.RequestURI()+`>; rel="`+rel+`"`)
+	}
+
+	link("first", func(url.Values) {})
+
+	if p.PrevCursor != "" {
+		link("prev", func(q url.Values) { q.Set(cursorQuery, p.PrevCursor) })
+	} else if p.Skip > 0 {
+		link("prev", func(q url.Values) {
+			if skip := p.Skip - p.Limit; skip > 0 {
+				q.Set(skipQuery, strconv.Itoa(skip))
+			}
+		})
+	}
+
+	if p.NextCursor != "" {
+		link("next", func(q url.Values) { q.Set(cursorQuery, p.NextCursor) })
+	} else if p.HasMore {
+		link("next", func(q url.Values) { q.Set(skipQuery, strconv.Itoa(p.Skip+p.Limit)) })
+	}
+
+	if p.Total > 0 && p.Limit > 0 {
+		link("last", func(q url.Values) {
+			q.Set(skipQuery, strconv.FormatInt((p.Total-1)/int64(p.Limit)*int64(p.Limit), 10))
+		})
+	}
+
+	return strings.Join(links, ", ")
 }
 
 func parseArrOrderBy(arr []string) []orderBy {
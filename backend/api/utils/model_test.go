@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type orderByAllower struct{}
@@ -225,9 +227,118 @@ func TestParseRequest(t *testing.T) {
 		},
 	} {
 		t.Run(each.description, func(t *testing.T) {
-			got := ParseRequest(each.input, orderByAllower{})
+			got, err := ParseRequest(each.input, orderByAllower{})
 
+			assert.NoError(t, err)
 			assert.Equal(t, each.want, got)
 		})
 	}
+
+	t.Run("cursor query param is not valid", func(t *testing.T) {
+		var pErr *ProblemError
+
+		_, err := ParseRequest(queryParserImpl{queryDb: map[string][]string{cursorQuery: {"tampered"}}}, orderByAllower{})
+
+		require.ErrorAs(t, err, &pErr)
+		assert.Equal(t, BadRequest, pErr.Type)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestSetLimits(t *testing.T) {
+	t.Cleanup(func() {
+		SetLimits(0, 0) //nolint:errcheck
+	})
+
+	assert.ErrorIs(t, SetLimits(20, 10), ErrInvalidLimits)
+	assert.ErrorIs(t, SetLimits(0, 10), ErrInvalidLimits)
+
+	assert.NoError(t, SetLimits(20, 200))
+
+	got, err := ParseRequest(queryParserImpl{queryDb: map[string][]string{}}, orderByAllower{})
+	assert.NoError(t, err)
+	assert.Equal(t, 20, got.Limit)
+
+	got, err = ParseRequest(queryParserImpl{queryDb: map[string][]string{limitQuery: {"150"}}}, orderByAllower{})
+	assert.NoError(t, err)
+	assert.Equal(t, 150, got.Limit)
+}
+
+func TestPageLinks(t *testing.T) {
+	u := url.URL{Path: "/food/categories", RawQuery: "order_by=name+desc&skip=20"}
+
+	for _, each := range []struct {
+		description string
+		input       Page[orderByAllower]
+		want        string
+	}{
+		{
+			description: "empty page only has the first link",
+			input:       Page[orderByAllower]{Limit: 10},
+			want:        `</food/categories?limit=10&order_by=name+desc>; rel="first"`,
+		},
+		{
+			description: "page using skip without cursors",
+			input:       Page[orderByAllower]{Limit: 10, Skip: 20, Total: 45, HasMore: true},
+			want: `</food/categories?limit=10&order_by=name+desc>; rel="first", ` +
+				`</food/categories?limit=10&order_by=name+desc&skip=10>; rel="prev", ` +
+				`</food/categories?limit=10&order_by=name+desc&skip=30>; rel="next", ` +
+				`</food/categories?limit=10&order_by=name+desc&skip=40>; rel="last"`,
+		},
+		{
+			description: "page using cursors",
+			input:       Page[orderByAllower]{Limit: 10, Total: 45, HasMore: true, NextCursor: "next", PrevCursor: "prev"},
+			want: `</food/categories?limit=10&order_by=name+desc>; rel="first", ` +
+				`</food/categories?cursor=prev&limit=10&order_by=name+desc>; rel="prev", ` +
+				`</food/categories?cursor=next&limit=10&order_by=name+desc>; rel="next", ` +
+				`</food/categories?limit=10&order_by=name+desc&skip=40>; rel="last"`,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, each.input.Links(u))
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	allowed := []string{"unit", "variety"}
+
+	got, err := ParseValues(queryParserImpl{queryDb: map[string][]string{"type": {"variety", "unit"}}}, "type", allowed)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"variety", "unit"}, got)
+
+	got, err = ParseValues(queryParserImpl{queryDb: map[string][]string{}}, "type", allowed)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	var pErr *ProblemError
+
+	_, err = ParseValues(queryParserImpl{queryDb: map[string][]string{"type": {"unit", "recipe"}}}, "type", allowed)
+	require.ErrorAs(t, err, &pErr)
+	assert.Equal(t, BadRequest, pErr.Type)
+	assert.EqualError(t, err, `type query must be one of [unit variety], got "recipe"`)
+}
+
+// countedRow is a row which carries the amount of rows without pagination.
+type countedRow struct {
+	Name  string
+	Total int64
+}
+
+func (r countedRow) RowsTotal() int64 {
+	return r.Total
+}
+
+func TestNewCountedPage(t *testing.T) {
+	w := WrapperRequest[orderByAllower]{Limit: 2, Skip: 2}
+
+	assert.Equal(t, Page[countedRow]{Items: Items[countedRow]{}, Limit: 2, Skip: 2}, NewCountedPage(w, []countedRow(nil)))
+
+	got := NewCountedPage(w, []countedRow{{Name: "c", Total: 5}, {Name: "d", Total: 5}})
+	assert.Equal(t, int64(5), got.Total)
+	assert.True(t, got.HasMore)
+	assert.Len(t, got.Items, 2)
+
+	got = NewCountedPage(WrapperRequest[orderByAllower]{Limit: 2, Skip: 4}, []countedRow{{Name: "e", Total: 5}})
+	assert.False(t, got.HasMore)
 }
@@ -0,0 +1,233 @@
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	// MIMEProblemJSON is the Content-Type of the problems encoded as JSON (RFC 7807).
+	MIMEProblemJSON = "application/problem+json"
+	// MIMEProblemXML is the Content-Type of the problems encoded as XML (RFC 7807).
+	MIMEProblemXML = "application/problem+xml"
+
+	// problemTypeBase is the prefix of the type URI of every problem.
+	problemTypeBase = "urn:go-home:problem:"
+
+	// uniqueViolation is the PostgreSQL error code of the unique constraint violations.
+	uniqueViolation = "23505"
+	// foreignKeyViolation is the PostgreSQL error code of the foreign key violations.
+	foreignKeyViolation = "23503"
+)
+
+// ErrInternal is the detail of the 5xx problems, whose errors are logged instead of responded
+// as they may describe the database.
+var ErrInternal = errors.New("the server failed unexpectedly, its logs can be found by the request_id")
+
+// ProblemType is each of the kinds of problems the API can respond with.
+type ProblemType struct {
+	Slug   string
+	Title  string
+	Status int
+}
+
+var (
+	// BadRequest is used when the request is malformed.
+	BadRequest = ProblemType{Slug: "bad_request", Title: "The request is malformed", Status: http.StatusBadRequest}
+	// NotFound is used when the resource requested doesn't exist.
+	NotFound = ProblemType{Slug: "not_found", Title: "The resource was not found", Status: http.StatusNotFound}
+	// Conflict is used when the request conflicts with the current state of the resource,
+	// like creating a resource whose name already exists.
+	Conflict = ProblemType{Slug: "conflict", Title: "The resource conflicts with an existing one", Status: http.StatusConflict}
+	// UnsupportedMediaType is used when the Content-Type of the request is not allowed.
+	UnsupportedMediaType = ProblemType{
+		Slug: "unsupported_media_type", Title: "The content type is not supported", Status: http.StatusUnsupportedMediaType,
+	}
+	// ValidationFailed is used when the body of the request has invalid fields.
+	ValidationFailed = ProblemType{
+		Slug: "validation_failed", Title: "The resource has invalid fields", Status: http.StatusUnprocessableEntity,
+	}
+	// ParentMissing is used when the parent of the resource doesn't exist.
+	ParentMissing = ProblemType{
+		Slug: "parent_missing", Title: "The parent of the resource was not found", Status: http.StatusUnprocessableEntity,
+	}
+	// TooManyRequests is used when the client exceeds the rate limit.
+	TooManyRequests = ProblemType{
+		Slug: "too_many_requests", Title: "Too many requests, try again later", Status: http.StatusTooManyRequests,
+	}
+	// Internal is used when something unexpected went wrong.
+	Internal = ProblemType{
+		Slug: "internal", Title: "The server failed to process the request", Status: http.StatusInternalServerError,
+	}
+
+	problemTypesByStatus = map[int]ProblemType{
+		BadRequest.Status:           BadRequest,
+		NotFound.Status:             NotFound,
+		Conflict.Status:             Conflict,
+		UnsupportedMediaType.Status: UnsupportedMediaType,
+		ValidationFailed.Status:     ValidationFailed,
+		TooManyRequests.Status:      TooManyRequests,
+		Internal.Status:             Internal,
+	}
+)
+
+// URI returns the type URI of the problem type. It is about:blank when the problem type
+// has no slug, meaning that the problem has no additional semantics beyond the status.
+func (p ProblemType) URI() string {
+	if p.Slug == "" {
+		return "about:blank"
+	}
+
+	return problemTypeBase + p.Slug
+}
+
+// ProblemError is an error which knows the type of problem it represents.
+type ProblemError struct {
+	Type   ProblemType
+	Err    error
+	Fields []FieldError
+	// Children are the resources which block the request, like the children of a resource
+	// which is being deleted without cascading.
+	Children []Node
+}
+
+// NewProblemError wraps err into an error of the problem type t.
+func NewProblemError(t ProblemType, err error, fields ...FieldError) *ProblemError {
+	return &ProblemError{Type: t, Err: err, Fields: fields}
+}
+
+// Error returns the message of the error wrapped.
+func (p *ProblemError) Error() string {
+	return p.Err.Error()
+}
+
+// Unwrap returns the error wrapped.
+func (p *ProblemError) Unwrap() error {
+	return p.Err
+}
+
+// FieldError is the problem of a single field of the request.
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Message string `json:"message" xml:"message"`
+}
+
+// Problem is the body of the error responses, following the RFC 7807.
+type Problem struct {
+	XMLName  xml.Name     `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type     string       `json:"type" xml:"type"`
+	Title    string       `json:"title" xml:"title"`
+	Status   int          `json:"status" xml:"status"`
+	Detail   string       `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty" xml:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty" xml:"errors>i,omitempty"`
+	Children []Node       `json:"children,omitempty" xml:"children>i,omitempty"`
+	// RequestID is the ID of the request, to find its logs.
+	RequestID string `json:"request_id,omitempty" xml:"request_id,omitempty"`
+}
+
+// NewProblem returns the problem of err. The type is taken from the ProblemError wrapped by
+// err or from the PostgreSQL error code. The RowErrors are a ValidationFailed problem with the
+// error of each row. Otherwise, the type is the one of statusCode. The detail is the message of
+// err, except for the 5xx problems and the PostgreSQL errors, which are only described by the
+// type because their messages have the internals of the database.
+func NewProblem(err error, statusCode int, instance string) Problem {
+	var (
+		t        = problemTypeOf(err, statusCode)
+		detail   = err.Error()
+		fields   []FieldError
+		children []Node
+		pErr     *ProblemError
+		pgErr    *pgconn.PgError
+		rowErrs  RowErrors
+	)
+
+	if errors.As(err, &pErr) {
+		fields, children = pErr.Fields, pErr.Children
+	} else if errors.As(err, &rowErrs) {
+		detail, fields = ErrRowsFailed.Error(), rowErrs.Fields()
+	}
+
+	if t.Status >= http.StatusInternalServerError {
+		detail = ErrInternal.Error()
+	} else if errors.As(err, &pgErr) {
+		detail = ""
+	}
+
+	return Problem{
+		Type:     t.URI(),
+		Title:    t.Title,
+		Status:   t.Status,
+		Detail:   detail,
+		Instance: instance,
+		Errors:   fields,
+		Children: children,
+	}
+}
+
+func problemTypeOf(err error, statusCode int) ProblemType {
+	var (
+		pErr    *ProblemError
+		pgErr   *pgconn.PgError
+		rowErrs RowErrors
+	)
+
+	switch {
+	case errors.As(err, &pErr):
+		return pErr.Type
+	case errors.As(err, &rowErrs):
+		return ValidationFailed
+	case errors.As(err, &pgErr) && pgErr.Code == uniqueViolation:
+		return Conflict
+	case errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation:
+		return ParentMissing
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return NotFound
+	}
+
+	if t, ok := problemTypesByStatus[statusCode]; ok {
+		return t
+	}
+
+	return ProblemType{Title: http.StatusText(statusCode), Status: statusCode}
+}
+
+// ErrRes responds with the problem of err, encoded as JSON or XML depending on the Accept
+// header. statusCode is used when the type of the problem can't be known from err. The problem
+// has the ID of the request, and err is attached to the request to be logged if it is a 5xx.
+func ErrRes(g *gin.Context, err error, statusCode int) {
+	problem := NewProblem(translateValidation(g, err), statusCode, g.Request.URL.Path)
+	problem.RequestID = config.RequestID(g.Request.Context())
+
+	if problem.Status >= http.StatusInternalServerError {
+		g.Error(err) //nolint:errcheck
+	}
+
+	switch g.NegotiateFormat(MIMEProblemJSON, gin.MIMEJSON, MIMEProblemXML, gin.MIMEXML, gin.MIMEXML2) {
+	case MIMEProblemXML, gin.MIMEXML, gin.MIMEXML2:
+		g.Header("Content-Type", MIMEProblemXML+"; charset=utf-8")
+		g.Render(problem.Status, render.XML{Data: problem})
+	default:
+		g.Header("Content-Type", MIMEProblemJSON+"; charset=utf-8")
+		g.Render(problem.Status, render.JSON{Data: problem})
+	}
+}
+
+// DeleteErr wraps the foreign key violations returned when deleting a resource as conflicts,
+// because they mean that the resource still has children instead of a missing parent.
+func DeleteErr(err error) error {
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return NewProblemError(Conflict, err)
+	}
+
+	return err
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestProblemTypeOf(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		err         error
+		statusCode  int
+		want        ProblemType
+	}{
+		{
+			description: "problem error wrapped",
+			err:         fmt.Errorf("adding unit: %w", NewProblemError(ParentMissing, errors.New("subcategory not found"))),
+			statusCode:  http.StatusInternalServerError,
+			want:        ParentMissing,
+		},
+		{
+			description: "unique violation",
+			err:         &pgconn.PgError{Code: uniqueViolation},
+			statusCode:  http.StatusInternalServerError,
+			want:        Conflict,
+		},
+		{
+			description: "foreign key violation",
+			err:         &pgconn.PgError{Code: foreignKeyViolation},
+			statusCode:  http.StatusInternalServerError,
+			want:        ParentMissing,
+		},
+		{
+			description: "foreign key violation when deleting",
+			err:         DeleteErr(&pgconn.PgError{Code: foreignKeyViolation}),
+			statusCode:  http.StatusInternalServerError,
+			want:        Conflict,
+		},
+		{
+			description: "record not found",
+			err:         gorm.ErrRecordNotFound,
+			statusCode:  http.StatusInternalServerError,
+			want:        NotFound,
+		},
+		{
+			description: "type taken from the status code",
+			err:         errors.New("bad input"),
+			statusCode:  http.StatusBadRequest,
+			want:        BadRequest,
+		},
+		{
+			description: "status code without type",
+			err:         errors.New("teapot"),
+			statusCode:  http.StatusTeapot,
+			want:        ProblemType{Title: http.StatusText(http.StatusTeapot), Status: http.StatusTeapot},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.Equal(t, each.want, problemTypeOf(each.err, each.statusCode))
+		})
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	var (
+		fields = []FieldError{{Field: "name", Message: "name is a required field"}}
+		got    = NewProblem(NewProblemError(ValidationFailed, ErrValidation, fields...), http.StatusBadRequest, "/food/categories")
+	)
+
+	assert.Equal(t, Problem{
+		Type:     "urn:go-home:problem:validation_failed",
+		Title:    ValidationFailed.Title,
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   ErrValidation.Error(),
+		Instance: "/food/categories",
+		Errors:   fields,
+	}, got)
+
+	assert.Equal(t, "about:blank", NewProblem(errors.New("teapot"), http.StatusTeapot, "").Type)
+}
+
+func TestNewProblemRowErrors(t *testing.T) {
+	got := NewProblem(RowErrors{
+		{Row: "rows[0]", Err: ErrAlreadyExists},
+		{Row: "rows[2]", Err: NewProblemError(ParentMissing, errors.New("unit not found"))},
+	}, http.StatusInternalServerError, "/food/import")
+
+	assert.Equal(t, Problem{
+		Type:     ValidationFailed.URI(),
+		Title:    ValidationFailed.Title,
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   ErrRowsFailed.Error(),
+		Instance: "/food/import",
+		Errors: []FieldError{
+			{Field: "rows[0]", Message: "there is already a resource with the same name"},
+			{Field: "rows[2]", Message: "unit not found"},
+		},
+	}, got)
+}
+
+func TestErrRes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, each := range []struct {
+		description     string
+		accept          string
+		wantContentType string
+		unmarshal       func([]byte, any) error
+	}{
+		{
+			description:     "json by default",
+			wantContentType: MIMEProblemJSON + "; charset=utf-8",
+			unmarshal:       json.Unmarshal,
+		},
+		{
+			description:     "xml when accepted",
+			accept:          gin.MIMEXML,
+			wantContentType: MIMEProblemXML + "; charset=utf-8",
+			unmarshal:       xml.Unmarshal,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				rec    = httptest.NewRecorder()
+				c, _   = gin.CreateTestContext(rec)
+				got    Problem
+				pgErr  = &pgconn.PgError{Code: uniqueViolation, Message: "duplicate key"}
+				target = "/api/v1/food/categories"
+			)
+
+			c.Request = httptest.NewRequest(http.MethodPost, target, nil)
+			c.Request = c.Request.WithContext(config.WithRequestID(c.Request.Context(), "42"))
+			if each.accept != "" {
+				c.Request.Header.Set("Accept", each.accept)
+			}
+
+			ErrRes(c, pgErr, http.StatusInternalServerError)
+
+			assert.Equal(t, http.StatusConflict, rec.Code)
+			assert.Equal(t, each.wantContentType, rec.Header().Get("Content-Type"))
+			assert.NoError(t, each.unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, Conflict.URI(), got.Type)
+			assert.Equal(t, Conflict.Title, got.Title)
+			assert.Equal(t, http.StatusConflict, got.Status)
+			assert.Equal(t, target, got.Instance)
+			assert.Equal(t, "42", got.RequestID)
+			assert.Empty(t, got.Detail, "the message of PostgreSQL is not responded")
+		})
+	}
+}
+
+func TestNewProblemInternal(t *testing.T) {
+	got := NewProblem(errors.New(`pq: relation "food_categories" does not exist`), http.StatusInternalServerError, "/food/categories")
+
+	assert.Equal(t, Internal.URI(), got.Type)
+	assert.Equal(t, ErrInternal.Error(), got.Detail)
+}
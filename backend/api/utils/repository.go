@@ -6,30 +6,50 @@ type RepositoryBuilder interface {
 	Build() *gorm.DB
 }
 
+// OrderByAllower is implemented by the models which can be ordered. The keys are the fields
+// allowed to order by and, if the value is a string, it is the column of the field.
 type OrderByAllower interface {
 	OrderByColumnsAllowed() map[string]any
 }
 
+// ToScope applies the filters and the pagination of the request to db. It fetches one row
+// more than the limit, so NewPage knows if there are more rows to paginate.
 func (w WrapperRequest[T]) ToScope(db *gorm.DB) *gorm.DB {
-	return w.limit(w.skip(w.orderBy(db)))
+	return w.limit(w.skip(w.orderBy(w.after(w.ToFilter(db)))))
+}
+
+func (w WrapperRequest[T]) after(db *gorm.DB) *gorm.DB {
+	if w.Cursor == nil {
+		return db
+	}
+
+	query, args := w.keysetCondition(w.keyset())
+
+	return db.Where(query, args...)
 }
 
 func (w WrapperRequest[T]) orderBy(db *gorm.DB) *gorm.DB {
-	var cols = w.Body.OrderByColumnsAllowed()
+	var backward = w.Cursor != nil && w.Cursor.Backward
 
-	for _, each := range w.OrderBy {
-		if _, ok := cols[each.Field]; ok {
-			db = db.Order(each.String())
+	for _, each := range w.keyset() {
+		if backward {
+			each.Direction = each.Direction.reverse()
 		}
+
+		db = db.Order(w.qualifiedColumn(each.Field) + " " + each.Direction.String())
 	}
 
 	return db
 }
 
 func (w WrapperRequest[T]) limit(db *gorm.DB) *gorm.DB {
-	return db.Limit(w.Limit)
+	return db.Limit(w.Limit + 1)
 }
 
 func (w WrapperRequest[T]) skip(db *gorm.DB) *gorm.DB {
+	if w.Cursor != nil {
+		return db
+	}
+
 	return db.Offset(w.Skip)
 }
@@ -2,13 +2,22 @@ package utils
 
 import (
 	"errors"
+	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// TotalCountHeader is the header which contains the total amount of resources of a list.
+	TotalCountHeader = "X-Total-Count"
+	// LinkHeader is the header which contains the links to the other pages of a list.
+	LinkHeader = "Link"
+)
+
 var (
 	// ErrContentTypeNotAllowed is used when request contains an incorrect Content-Type.
-	errContentTypeNotAllowed = errors.New("content type not allowed")
+	ErrContentTypeNotAllowed = NewProblemError(UnsupportedMediaType, errors.New("content type not allowed"))
 
 	// Negotiate is used to express which Accept and Content-Type MIME types are allowed.
 	Negotiate = []string{gin.MIMEJSON, gin.MIMEXML}
@@ -24,12 +33,35 @@ type QueryParser interface {
 	QueryArray(string) []string
 }
 
-func ErrRes(g *gin.Context, err error, statusCode int) {
-	g.Negotiate(statusCode, gin.Negotiate{
+// PageRes responds with the page, adding the X-Total-Count header and the Link header
+// with the first, prev, next and last pages.
+func PageRes[T any](g *gin.Context, page Page[T]) {
+	g.Header(TotalCountHeader, strconv.FormatInt(page.Total, 10))
+	g.Header(LinkHeader, page.Links(*g.Request.URL))
+
+	g.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered: Negotiate,
-		Data: WrapperResponse{
-			Code: statusCode,
-			Msg:  err.Error(),
-		},
+		Data:    page,
 	})
 }
+
+// MergePatchBody returns the JSON Merge Patch document of the request, checking that
+// the Content-Type is the merge patch one or plain JSON, and that it can patch a T, so
+// malformed patches are rejected before the resource is looked up.
+func MergePatchBody[T any](c *gin.Context) ([]byte, error) {
+	if ct := c.ContentType(); ct != MIMEMergePatchJSON && ct != gin.MIMEJSON {
+		return nil, ErrContentTypeNotAllowed
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+
+	var zero T
+	if _, err = ApplyMergePatch(zero, patch); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
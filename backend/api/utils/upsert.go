@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const modeQuery = "mode"
+
+var (
+	// ErrAlreadyExists is used when inserting a resource whose name is already taken, even by
+	// a resource in the trash.
+	ErrAlreadyExists = NewProblemError(Conflict, errors.New("there is already a resource with the same name"))
+	// ErrRowsFailed is used when some of the resources of a request with many of them failed.
+	ErrRowsFailed = errors.New("some of the resources failed, nothing was saved")
+)
+
+// Mode is how the resources which already exist are handled when they are loaded in bulk.
+type Mode string
+
+const (
+	// Insert fails if there is already a resource with the same name.
+	Insert Mode = "insert"
+	// Upsert updates the resource with the same name, restoring it from the trash if needed.
+	Upsert Mode = "upsert"
+	// Replace upserts the resources loaded and moves the other ones to the trash.
+	Replace Mode = "replace"
+)
+
+// Modes are all the modes allowed.
+var Modes = []Mode{Insert, Upsert, Replace}
+
+// ParseMode returns the mode named s, or def if s is empty.
+func ParseMode(s string, def Mode) (Mode, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	for _, each := range Modes {
+		if string(each) == s {
+			return each, nil
+		}
+	}
+
+	return def, NewProblemError(BadRequest, fmt.Errorf("%s must be one of %v, got %q", modeQuery, Modes, s))
+}
+
+// ParseModeQuery parses the mode query value, which is def by default.
+func ParseModeQuery(q QueryParser, def Mode) (Mode, error) {
+	return ParseMode(q.Query(modeQuery), def)
+}
+
+// Outcome is what an upsert did with a resource.
+type Outcome string
+
+const (
+	// Created is used when there was no resource with the same name.
+	Created Outcome = "created"
+	// Updated is used when the resource with the same name was different or was in the trash.
+	Updated Outcome = "updated"
+	// Unchanged is used when the resource with the same name was already the same.
+	Unchanged Outcome = "unchanged"
+	// Deleted is used when the resource was moved to the trash because it was not loaded
+	// in Replace mode.
+	Deleted Outcome = "deleted"
+)
+
+// Counts are the amount of resources of each outcome after several upserts.
+type Counts struct {
+	Created   int `json:"created" yaml:"created" xml:"created,attr"`
+	Updated   int `json:"updated" yaml:"updated" xml:"updated,attr"`
+	Unchanged int `json:"unchanged" yaml:"unchanged" xml:"unchanged,attr"`
+	Deleted   int `json:"deleted" yaml:"deleted" xml:"deleted,attr"`
+}
+
+// Add counts one more resource with the outcome o.
+func (c *Counts) Add(o Outcome) {
+	switch o {
+	case Created:
+		c.Created++
+	case Updated:
+		c.Updated++
+	case Unchanged:
+		c.Unchanged++
+	case Deleted:
+		c.Deleted++
+	}
+}
+
+// String returns the counts as "<created> created, <updated> updated, <unchanged> unchanged",
+// followed by ", <deleted> deleted" if any resource was deleted.
+func (c Counts) String() string {
+	s := fmt.Sprintf("%d created, %d updated, %d unchanged", c.Created, c.Updated, c.Unchanged)
+	if c.Deleted > 0 {
+		s += fmt.Sprintf(", %d deleted", c.Deleted)
+	}
+
+	return s
+}
+
+// RowError is the error of one of the resources of a request with many of them.
+type RowError struct {
+	// Row is the path of the resource inside of the request, like categories[0].subcategories[1].
+	Row string
+	Err error
+}
+
+// RowErrors are the errors of the resources which failed in a request with many of them.
+type RowErrors []RowError
+
+// Fields returns a FieldError for each row, whose field is the path of the row.
+func (r RowErrors) Fields() []FieldError {
+	fields := make([]FieldError, 0, len(r))
+
+	for _, each := range r {
+		fields = append(fields, FieldError{Field: each.Row, Message: each.Err.Error()})
+	}
+
+	return fields
+}
+
+// Error returns the error of each row, one per line.
+func (r RowErrors) Error() string {
+	lines := make([]string, 0, len(r)+1)
+	lines = append(lines, ErrRowsFailed.Error())
+
+	for _, each := range r {
+		lines = append(lines, each.Row+": "+each.Err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
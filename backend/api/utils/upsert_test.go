@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountsAdd(t *testing.T) {
+	var got Counts
+
+	for _, each := range []Outcome{Created, Updated, Created, Unchanged, ""} {
+		got.Add(each)
+	}
+
+	assert.Equal(t, Counts{Created: 2, Updated: 1, Unchanged: 1}, got)
+	assert.Equal(t, "2 created, 1 updated, 1 unchanged", got.String())
+
+	got.Add(Deleted)
+	assert.Equal(t, "2 created, 1 updated, 1 unchanged, 1 deleted", got.String())
+}
+
+func TestParseModeQuery(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       queryValues
+		want        Mode
+		wantErr     bool
+	}{
+		{
+			description: "default mode",
+			input:       queryValues{},
+			want:        Insert,
+		},
+		{
+			description: "replace mode",
+			input:       queryValues{"mode": {"replace"}},
+			want:        Replace,
+		},
+		{
+			description: "unknown mode",
+			input:       queryValues{"mode": {"merge"}},
+			want:        Insert,
+			wantErr:     true,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := ParseModeQuery(each.input, Insert)
+			if each.wantErr {
+				assert.Equal(t, BadRequest, problemTypeOf(err, 0))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestRowErrors(t *testing.T) {
+	err := RowErrors{
+		{Row: "rows[0]", Err: ErrAlreadyExists},
+		{Row: "rows[3]", Err: errors.New("unit not found")},
+	}
+
+	assert.Equal(t, ErrRowsFailed.Error()+"\n"+
+		"rows[0]: there is already a resource with the same name\n"+
+		"rows[3]: unit not found", err.Error())
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+)
+
+const (
+	// foodNameTag validates that the name of a resource only contains letters, digits, spaces
+	// and some punctuation marks, starting with a letter or a digit.
+	foodNameTag = "food_name"
+	// trimmedTag validates that a string doesn't start or end with whitespaces.
+	trimmedTag = "trimmed"
+	// imgURLTag validates that a string is an absolute http or https URL.
+	imgURLTag = "img_url"
+
+	// acceptLanguageHeader is the header used to choose the language of the validation messages.
+	acceptLanguageHeader = "Accept-Language"
+)
+
+var (
+	// ErrValidation is the detail of the problems returned when the request has invalid fields.
+	ErrValidation = errors.New("the request has invalid fields")
+
+	foodNameRegex = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} '.,&()\-]*$`)
+
+	universal   *ut.UniversalTranslator
+	register    sync.Once
+	errRegister error
+)
+
+// customTranslations are the messages of the custom validations for each of the locales.
+var customTranslations = map[string]map[string]string{
+	"en": {
+		foodNameTag: "{0} can only contain letters, digits, spaces and the characters ' . , & ( ) -",
+		trimmedTag:  "{0} cannot start or end with whitespaces",
+		imgURLTag:   "{0} must be an absolute http or https URL",
+	},
+	"es": {
+		foodNameTag: "{0} solo puede contener letras, dígitos, espacios y los caracteres ' . , & ( ) -",
+		trimmedTag:  "{0} no puede empezar ni terminar con espacios",
+		imgURLTag:   "{0} debe ser una URL absoluta http o https",
+	},
+}
+
+// RegisterValidations registers the custom validations and the translations of the messages
+// into the validator used by gin when binding the requests. It must be called before serving
+// any request, and calling it more than once does nothing.
+func RegisterValidations() error {
+	register.Do(func() {
+		errRegister = registerValidations()
+	})
+
+	return errRegister
+}
+
+func registerValidations() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("gin validator is not go-playground validator")
+	}
+
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	for tag, fn := range map[string]validator.Func{foodNameTag: isFoodName, trimmedTag: isTrimmed, imgURLTag: isImgURL} {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+
+	english := en.New()
+	universal = ut.New(english, english, es.New())
+
+	for locale, registerDefaults := range map[string]func(*validator.Validate, ut.Translator) error{
+		"en": en_translations.RegisterDefaultTranslations,
+		"es": es_translations.RegisterDefaultTranslations,
+	} {
+		trans, _ := universal.GetTranslator(locale)
+		if err := registerDefaults(v, trans); err != nil {
+			return err
+		}
+
+		for tag, msg := range customTranslations[locale] {
+			if err := v.RegisterTranslation(tag, trans, registerTranslation(tag, msg), translate); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate validates obj using the binding tags of its fields, the same way gin does
+// when binding the body of a request.
+func Validate(obj any) error {
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// translateValidation wraps the validation errors of err into a problem with all the fields
+// that failed, translating their messages to the language requested in the Accept-Language
+// header. The errors of each row of RowErrors are wrapped the same way, prefixing the fields
+// with the row. Any other error is returned as it is.
+func translateValidation(g *gin.Context, err error) error {
+	var (
+		vErrs   validator.ValidationErrors
+		rowErrs RowErrors
+		trans   = translator(g.GetHeader(acceptLanguageHeader))
+	)
+
+	if errors.As(err, &rowErrs) {
+		var fields []FieldError
+
+		for _, each := range rowErrs {
+			if errors.As(each.Err, &vErrs) {
+				fields = append(fields, validationFields(trans, vErrs, each.Row+".")...)
+			} else {
+				fields = append(fields, RowErrors{each}.Fields()...)
+			}
+		}
+
+		return NewProblemError(ValidationFailed, ErrRowsFailed, fields...)
+	}
+
+	if !errors.As(err, &vErrs) {
+		return err
+	}
+
+	return NewProblemError(ValidationFailed, ErrValidation, validationFields(trans, vErrs, "")...)
+}
+
+// validationFields returns the fields of vErrs with their messages translated by trans,
+// prefixing their paths with prefix.
+func validationFields(trans ut.Translator, vErrs validator.ValidationErrors, prefix string) []FieldError {
+	fields := make([]FieldError, 0, len(vErrs))
+
+	for _, each := range vErrs {
+		msg := each.Error()
+		if trans != nil {
+			msg = each.Translate(trans)
+		}
+
+		fields = append(fields, FieldError{Field: prefix + fieldPath(each), Message: msg})
+	}
+
+	return fields
+}
+
+// translator returns the translator of the first locale of the Accept-Language header which
+// is supported, falling back to english. It is nil if the validations weren't registered.
+func translator(acceptLanguage string) ut.Translator {
+	if universal == nil {
+		return nil
+	}
+
+	var locales []string
+	for _, each := range strings.Split(acceptLanguage, ",") {
+		locale, _, _ := strings.Cut(strings.TrimSpace(each), ";")
+		locale, _, _ = strings.Cut(locale, "-")
+		if locale != "" {
+			locales = append(locales, strings.ToLower(locale))
+		}
+	}
+
+	trans, _ := universal.FindTranslator(locales...)
+
+	return trans
+}
+
+// fieldPath returns the path of the field without the name of the root struct.
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+
+	return fe.Field()
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+
+	return field.Name
+}
+
+func registerTranslation(tag, msg string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, msg, true)
+	}
+}
+
+func translate(trans ut.Translator, fe validator.FieldError) string {
+	msg, err := trans.T(fe.Tag(), fe.Field())
+	if err != nil {
+		return fe.Error()
+	}
+
+	return msg
+}
+
+func isFoodName(fl validator.FieldLevel) bool {
+	return foodNameRegex.MatchString(fl.Field().String())
+}
+
+func isTrimmed(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value == strings.TrimSpace(value)
+}
+
+func isImgURL(fl validator.FieldLevel) bool {
+	u, err := url.ParseRequestURI(fl.Field().String())
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
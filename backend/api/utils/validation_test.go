@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type validationModel struct {
+	Name        string `json:"name" binding:"required,min=2,max=64,trimmed,food_name"`
+	Description string `json:"description" binding:"required,min=5"`
+	Img         string `json:"img" binding:"omitempty,img_url"`
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, RegisterValidations())
+
+	for _, each := range []struct {
+		description string
+		input       validationModel
+		wantErr     bool
+	}{
+		{
+			description: "valid model",
+			input:       validationModel{Name: "Granny Smith's (green)", Description: "tart apple", Img: "https://example.com/apple.png"},
+		},
+		{
+			description: "name with leading whitespaces",
+			input:       validationModel{Name: " apple", Description: "tart apple"},
+			wantErr:     true,
+		},
+		{
+			description: "name with characters not allowed",
+			input:       validationModel{Name: "apple;drop", Description: "tart apple"},
+			wantErr:     true,
+		},
+		{
+			description: "img is not an http url",
+			input:       validationModel{Name: "apple", Description: "tart apple", Img: "ftp://example.com/apple.png"},
+			wantErr:     true,
+		},
+		{
+			description: "img is relative",
+			input:       validationModel{Name: "apple", Description: "tart apple", Img: "/apple.png"},
+			wantErr:     true,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			err := Validate(each.input)
+			if each.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestErrResValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	assert.NoError(t, RegisterValidations())
+
+	for _, each := range []struct {
+		description    string
+		acceptLanguage string
+		want           []FieldError
+	}{
+		{
+			description: "english by default",
+			want: []FieldError{
+				{Field: "name", Message: "name cannot start or end with whitespaces"},
+				{Field: "description", Message: "description is a required field"},
+			},
+		},
+		{
+			description:    "spanish when it is preferred",
+			acceptLanguage: "es-ES,es;q=0.9,en;q=0.8",
+			want: []FieldError{
+				{Field: "name", Message: "name no puede empezar ni terminar con espacios"},
+				{Field: "description", Message: "description es un campo requerido"},
+			},
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			var (
+				rec   = httptest.NewRecorder()
+				c, _  = gin.CreateTestContext(rec)
+				model validationModel
+				got   Problem
+			)
+
+			c.Request = httptest.NewRequest(http.MethodPost, "/food/categories", strings.NewReader(`{"name":"apple "}`))
+			c.Request.Header.Set("Content-Type", gin.MIMEJSON)
+			c.Request.Header.Set("Accept-Language", each.acceptLanguage)
+
+			ErrRes(c, c.ShouldBind(&model), http.StatusBadRequest)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, ValidationFailed.URI(), got.Type)
+			assert.Equal(t, each.want, got.Errors)
+		})
+	}
+}
+
+func TestErrResRowErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	assert.NoError(t, RegisterValidations())
+
+	var (
+		rec  = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(rec)
+		got  Problem
+	)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/food/import", nil)
+
+	ErrRes(c, RowErrors{
+		{Row: "rows[0]", Err: Validate(validationModel{Name: "apple ", Description: "a fruit"})},
+		{Row: "rows[2]", Err: ErrAlreadyExists},
+	}, http.StatusBadRequest)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, ErrRowsFailed.Error(), got.Detail)
+	assert.Equal(t, []FieldError{
+		{Field: "rows[0].name", Message: "name cannot start or end with whitespaces"},
+		{Field: "rows[2]", Message: "there is already a resource with the same name"},
+	}, got.Errors)
+}
@@ -0,0 +1,130 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generate writes a CA along with a server and a client certificate into dir.
+func generate(t *testing.T, dir string) KeyPair {
+	ca, err := NewCA("test CA", time.Hour)
+	require.NoError(t, err)
+
+	server, err := ca.Issue("server", []string{"localhost", "127.0.0.1"}, x509.ExtKeyUsageServerAuth, time.Hour)
+	require.NoError(t, err)
+
+	client, err := ca.Issue("client", nil, x509.ExtKeyUsageClientAuth, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, ca.Write(filepath.Join(dir, CAFile), filepath.Join(dir, CAKeyFile)))
+	require.NoError(t, server.Write(filepath.Join(dir, ServerCertFile), filepath.Join(dir, ServerKeyFile)))
+	require.NoError(t, client.Write(filepath.Join(dir, ClientCertFile), filepath.Join(dir, ClientKeyFile)))
+
+	return server
+}
+
+func newTLS(dir string) c.TLS {
+	return c.TLS{
+		CertFile:     filepath.Join(dir, ServerCertFile),
+		KeyFile:      filepath.Join(dir, ServerKeyFile),
+		ClientCAFile: filepath.Join(dir, CAFile),
+	}
+}
+
+// handshake connects to the server of the config, presenting the client certificate of dir if
+// it is true, and returns the certificate of the server.
+func handshake(t *testing.T, config *tls.Config, dir string, withClientCert bool) (*x509.Certificate, error) {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			conn.(*tls.Conn).Handshake() //nolint:errcheck
+			conn.Close()
+		}
+	}()
+
+	ca, err := os.ReadFile(filepath.Join(dir, CAFile))
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(ca))
+
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12}
+	if withClientCert {
+		client, err := tls.LoadX509KeyPair(filepath.Join(dir, ClientCertFile), filepath.Join(dir, ClientKeyFile))
+		require.NoError(t, err)
+		clientConfig.Certificates = []tls.Certificate{client}
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", l.Addr().String(), clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// The server verifies the client certificate after the client has finished its handshake in TLS 1.3.
+	if _, err := conn.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestReloader(t *testing.T) {
+	c.ConfigureLogger(c.Logger{})
+
+	t.Run("mutual tls", func(t *testing.T) {
+		dir := t.TempDir()
+		generate(t, dir)
+
+		r, err := NewReloader(newTLS(dir))
+		require.NoError(t, err)
+
+		_, err = handshake(t, r.TLSConfig(), dir, true)
+		assert.NoError(t, err)
+
+		_, err = handshake(t, r.TLSConfig(), dir, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("files missing", func(t *testing.T) {
+		_, err := NewReloader(newTLS(t.TempDir()))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("reloads the files when they change", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		dir := t.TempDir()
+		generate(t, dir)
+
+		r, err := NewReloader(newTLS(dir))
+		require.NoError(t, err)
+
+		go r.Watch(ctx) //nolint:errcheck
+		time.Sleep(100 * time.Millisecond)
+
+		next := generate(t, dir)
+
+		assert.Eventually(t, func() bool {
+			got, err := handshake(t, r.TLSConfig(), dir, true)
+			return err == nil && got.SerialNumber.Cmp(next.Cert.SerialNumber) == 0
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+}
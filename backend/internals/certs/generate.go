@@ -0,0 +1,146 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// CAFile is the name of the file with the certificate of the development CA.
+	CAFile = "ca.pem"
+	// CAKeyFile is the name of the file with the private key of the development CA.
+	CAKeyFile = "ca-key.pem"
+	// ServerCertFile is the name of the file with the certificate of the server.
+	ServerCertFile = "server.pem"
+	// ServerKeyFile is the name of the file with the private key of the server.
+	ServerKeyFile = "server-key.pem"
+	// ClientCertFile is the name of the file with the certificate of a client, used for mutual TLS.
+	ClientCertFile = "client.pem"
+	// ClientKeyFile is the name of the file with the private key of the client.
+	ClientKeyFile = "client-key.pem"
+
+	// Organization is the organization of all the certificates generated.
+	Organization = "go-home development"
+)
+
+// KeyPair is a certificate along with its private key.
+type KeyPair struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// NewCA returns a self-signed CA, valid for validFor from now, which can issue the certificates
+// of the server and the clients.
+func NewCA(commonName string, validFor time.Duration) (KeyPair, error) {
+	template, err := newTemplate(commonName, validFor)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.MaxPathLenZero = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	return sign(template, nil)
+}
+
+// Issue returns a certificate signed by the CA kp, valid for validFor from now for the usage. The
+// hosts are the DNS names and IP addresses of the certificate, needed by the servers.
+func (kp KeyPair) Issue(commonName string, hosts []string, usage x509.ExtKeyUsage, validFor time.Duration) (KeyPair, error) {
+	template, err := newTemplate(commonName, validFor)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+
+	for _, each := range hosts {
+		if ip := net.ParseIP(each); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, each)
+		}
+	}
+
+	return sign(template, &kp)
+}
+
+// Write writes the certificate and the private key of kp as PEM into certFile and keyFile. The key
+// is only readable by its owner.
+func (kp KeyPair) Write(certFile, keyFile string) error {
+	key, err := x509.MarshalPKCS8PrivateKey(kp.Key)
+	if err != nil {
+		return err
+	}
+
+	if err := writePEM(keyFile, "PRIVATE KEY", key, 0600); err != nil {
+		return err
+	}
+
+	return writePEM(certFile, "CERTIFICATE", kp.Cert.Raw, 0644)
+}
+
+func newTemplate(commonName string, validFor time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{Organization}},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(validFor),
+	}, nil
+}
+
+// sign creates the certificate of template with a new key, signed by parent or self-signed if it is nil.
+func sign(template *x509.Certificate, parent *KeyPair) (KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.Cert, parent.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	return KeyPair{Cert: cert, Key: key}, nil
+}
+
+func writePEM(path, blockType string, content []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if err := pem.Encode(f, &pem.Block{Type: blockType, Bytes: content}); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,133 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"path/filepath"
+	"sync"
+	"time"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// debounce is how long Watch waits for more changes before reloading, because the certificate
+// and the key are usually written one after the other.
+const debounce = 200 * time.Millisecond
+
+// Reloader keeps the tls.Config of the server up to date with the files of its configuration.
+type Reloader struct {
+	tls c.TLS
+
+	mu      sync.RWMutex
+	current *tls.Config
+}
+
+// NewReloader returns a Reloader of the configuration t, reading its files for the first time.
+func NewReloader(t c.TLS) (*Reloader, error) {
+	r := &Reloader{tls: t}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload reads the files again. If any of them is not valid, the previous configuration is kept.
+func (r *Reloader) Reload() error {
+	next, err := r.tls.Load()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current = next
+
+	return nil
+}
+
+// TLSConfig returns the configuration for http.Server, which uses the last files loaded for each
+// new connection.
+func (r *Reloader) TLSConfig() *tls.Config {
+	current := r.config()
+
+	return &tls.Config{
+		MinVersion: current.MinVersion,
+		NextProtos: current.NextProtos,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.config().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config(), nil
+		},
+	}
+}
+
+func (r *Reloader) config() *tls.Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current
+}
+
+// Watch reloads the files whenever they change until ctx is done. The directories of the files are
+// watched instead of the files, so they can be replaced by renaming or by swapping symlinks, as
+// kubernetes does with the secrets mounted through the ..data symlink.
+func (r *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	files, dirs := make(map[string]bool), make(map[string]bool)
+	for _, each := range r.tls.Files() {
+		path, err := filepath.Abs(each)
+		if err != nil {
+			return err
+		}
+
+		if dir := filepath.Dir(path); !dirs[dir] {
+			if err := watcher.Add(dir); err != nil {
+				return err
+			}
+			dirs[dir] = true
+		}
+
+		files[path] = true
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if path, _ := filepath.Abs(event.Name); files[path] || filepath.Base(path) == "..data" {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			c.Warn("watching the tls files", zap.Error(err))
+		case <-timer.C:
+			if err := r.Reload(); err != nil {
+				c.Warn("reloading the tls files, keeping the previous ones", zap.Error(err))
+				continue
+			}
+
+			c.Info("tls files reloaded", zap.Strings("files", r.tls.Files()))
+		}
+	}
+}
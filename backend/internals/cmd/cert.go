@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/internals/certs"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultCertsDir is where the cert generate command writes the files.
+	DefaultCertsDir = "certs"
+	// DefaultCertValidity is how long the certificates generated are valid.
+	DefaultCertValidity = 365 * 24 * time.Hour
+
+	hostFlag     = "host"
+	validForFlag = "valid-for"
+	forceFlag    = "force"
+)
+
+// ErrCertExists is used when the cert generate command would overwrite a file without --force.
+var ErrCertExists = errors.New("file already exists, use --force to overwrite it")
+
+// NewCertCmd returns the command which manages the certificates of the API.
+func NewCertCmd() *cobra.Command {
+	certCmd := &cobra.Command{
+		Use:   "cert",
+		Short: "Manage the TLS certificates of the API",
+		Long:  "Manage the TLS certificates of the API. The config file is not read and the database is not connected to.",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return applyShorthands(cmd)
+		},
+	}
+
+	certCmd.AddCommand(newCertGenerateCmd())
+
+	return certCmd
+}
+
+func newCertGenerateCmd() *cobra.Command {
+	var (
+		dir      string
+		hosts    []string
+		validFor time.Duration
+		force    bool
+	)
+
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a local CA along with a server and a client certificate for development",
+		Long: "Generate a self-signed CA along with a server certificate for the hosts and a client certificate, " +
+			"both signed by it. Use server.tls.cert_file and server.tls.key_file to serve the API over TLS, and " +
+			"server.tls.client_ca_file with the CA to require the client certificate. They are not meant for production.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files := []string{
+				certs.CAFile, certs.CAKeyFile, certs.ServerCertFile, certs.ServerKeyFile, certs.ClientCertFile, certs.ClientKeyFile,
+			}
+
+			for i := range files {
+				files[i] = filepath.Join(dir, files[i])
+				if _, err := os.Stat(files[i]); err == nil && !force {
+					return fmt.Errorf("%w: %s", ErrCertExists, files[i])
+				}
+			}
+
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+
+			ca, err := certs.NewCA("go-home development CA", validFor)
+			if err != nil {
+				return err
+			}
+
+			server, err := ca.Issue("go-home", hosts, x509.ExtKeyUsageServerAuth, validFor)
+			if err != nil {
+				return err
+			}
+
+			client, err := ca.Issue("go-home client", nil, x509.ExtKeyUsageClientAuth, validFor)
+			if err != nil {
+				return err
+			}
+
+			for i, each := range []certs.KeyPair{ca, server, client} {
+				if err := each.Write(files[2*i], files[2*i+1]); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "created %s and %s\n", files[2*i], files[2*i+1])
+			}
+
+			return nil
+		},
+	}
+
+	flags := generateCmd.Flags()
+	flags.StringVar(&dir, dirFlag, DefaultCertsDir, "directory of the files")
+	flags.StringSliceVar(&hosts, hostFlag, []string{"localhost", "127.0.0.1", "::1"}, "DNS names and IP addresses of the server")
+	flags.DurationVar(&validFor, validForFlag, DefaultCertValidity, "how long the certificates are valid")
+	flags.BoolVar(&force, forceFlag, false, "overwrite the files which already exist")
+
+	return generateCmd
+}
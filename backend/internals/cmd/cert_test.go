@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/internals/certs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandsWithoutDatabase(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		args        func(dir string) []string
+		want        string
+	}{
+		{
+			description: "cert generate",
+			args:        func(dir string) []string { return []string{"cert", "generate", "--dir", dir} },
+			want:        certs.CAFile,
+		},
+		{
+			description: "migrate create",
+			args:        func(dir string) []string { return []string{"migrate", "create", "add_notes", "--dir", dir} },
+			want:        "*_add_notes.up.sql",
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			dir := t.TempDir()
+			rootCmd := NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetArgs(append(each.args(dir), "--db-host", "unreachable.invalid"))
+
+			require.NoError(t, rootCmd.Execute())
+
+			files, err := filepath.Glob(filepath.Join(dir, each.want))
+			require.NoError(t, err)
+			assert.Len(t, files, 1)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -29,36 +30,45 @@ var (
 // starts executing, it will trigger all config files and parameters needed
 // to get the job done
 func NewRootCmd() *cobra.Command {
-	return &cobra.Command{
+	rootCmd := &cobra.Command{
 		Use:   "go-home",
 		Short: "Just the main entrypoint to execute go-home API",
-		Long:  "Just the main entrypoint to execute go-home API",
-		Run: func(cmd *cobra.Command, args []string) {
-
+		Long: "Just the main entrypoint to execute go-home API. Use go-home serve to serve it.\n\n" +
+			"Every setting of the config file can be overridden by a flag named after its key, like " +
+			"--logger-console-appender-level, or by an environment variable prefixed by " + EnvPrefix + "_, like " +
+			EnvPrefix + "_LOGGER_CONSOLE_APPENDER_LEVEL. The precedence is flags > environment > config file > defaults.",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyShorthands(cmd); err != nil {
+				return err
+			}
+
+			return readConfig(cmd.Context())
 		},
 	}
-}
 
-func readConfig() {
-	if err := checkConfigFile(); err != nil {
-		panic(err)
-	}
+	addConfigFlags(rootCmd)
 
-	viper.SetConfigType(ConfigFileType)
-	viper.SetConfigName(ConfigFileName)
+	rootCmd.AddCommand(NewServeCmd(), NewPurgeCmd(), NewMigrateCmd(), NewSeedCmd(), NewImportCmd(), NewExportCmd(), NewCertCmd(), NewConfigCmd(), NewHealthcheckCmd())
 
-	viper.AutomaticEnv()
+	return rootCmd
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		panic(err)
+// readConfig reads and validates the configuration, which is applied before running any command.
+func readConfig(ctx context.Context) error {
+	next, unknown, err := loadConfig()
+	if err != nil {
+		return err
+	} else if err = validateConfig(next, unknown); err != nil {
+		return err
 	}
 
-	if err := viper.Unmarshal(&cfg); err != nil {
-		panic(err)
+	cfg = next
+
+	if err = applyConfig(cfg); err != nil {
+		return err
 	}
 
-	c.ConfigureLogger(cfg.Logger)
-	c.ConfigureDB(cfg.Database)
+	return c.ConfigureDB(ctx, cfg.Database)
 }
 
 func checkConfigFile() error {
@@ -82,8 +92,6 @@ func fileExists(dir string) bool {
 
 // Execute is the method called by main file to start the application.
 func Execute() error {
-	cobra.OnInitialize(readConfig)
-
 	rootCmd := NewRootCmd()
 
 	return rootCmd.Execute()
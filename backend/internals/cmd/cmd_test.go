@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 )
@@ -91,24 +92,32 @@ func TestReadConfig(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	t.Cleanup(viper.Reset)
+
 	t.Run("config file at home directory successfully readed", func(t *testing.T) {
+		viper.Reset()
 		backupConfigFile(t, pwd, configFile)
 		backupConfigFile(t, home, configFile)
 
 		_ = createConfigFile(t, home, configFile, string(want))
 
-		assert.Nil(t, Execute())
-		assert.Equal(t, config, cfg)
+		got, _, err := loadConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, config.Logger, got.Logger)
 	})
 
 	t.Run("config file at current directory successfully readed", func(t *testing.T) {
+		viper.Reset()
 		backupConfigFile(t, pwd, configFile)
 		backupConfigFile(t, home, configFile)
 
 		_ = createConfigFile(t, pwd, configFile, string(want))
 
-		assert.Nil(t, Execute())
-		assert.Equal(t, config, cfg)
+		got, _, err := loadConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, config.Logger, got.Logger)
 	})
 
 	t.Run("there is no config file, so it should return err checking config file", func(t *testing.T) {
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SchemaFile is the file of the JSON Schema of the config file, relative to the backend directory.
+	SchemaFile = "go-home.schema.json"
+
+	effectiveFlag = "effective"
+)
+
+// NewConfigCmd returns the command which validates and inspects the configuration. Its subcommands
+// read the config file themselves, so they report the problems instead of failing before running.
+func NewConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Validate and inspect the configuration",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return applyShorthands(cmd)
+		},
+	}
+
+	configCmd.AddCommand(newConfigValidateCmd(), newConfigPrintCmd(), newConfigSchemaCmd())
+
+	return configCmd
+}
+
+func newConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the whole configuration, reporting all the problems at once",
+		Long: "Check the whole configuration: the settings which don't exist, the database and its password file, " +
+			"the logger levels, date formats and files, the server, the pagination, the CORS and the rate limit. " +
+			"The database is not connected to.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			next, unused, err := loadConfig()
+			if err != nil {
+				return err
+			} else if err = validateConfig(next, unused); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", viper.ConfigFileUsed())
+
+			return nil
+		},
+	}
+}
+
+func newConfigPrintCmd() *cobra.Command {
+	var effective bool
+
+	printCmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the config file",
+		Long: "Print the config file or, with --effective, the configuration the API would use: the config file " +
+			"along with the defaults, the environment and the flags, without the password of the database.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			next, _, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			if !effective {
+				f, err := os.Open(viper.ConfigFileUsed())
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				_, err = io.Copy(cmd.OutOrStdout(), f)
+				return err
+			}
+
+			e := yaml.NewEncoder(cmd.OutOrStdout())
+			e.SetIndent(2)
+			if err := e.Encode(next.Redacted()); err != nil {
+				return err
+			}
+
+			return e.Close()
+		},
+	}
+
+	printCmd.Flags().BoolVar(&effective, effectiveFlag, false, "print the configuration with the defaults, the environment and the flags")
+
+	return printCmd
+}
+
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the config file",
+		Long: "Print the JSON Schema of the config file, which editors use to autocomplete and check it. The one " +
+			"of the repository is " + SchemaFile + ", generated with make schema.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e := json.NewEncoder(cmd.OutOrStdout())
+			e.SetIndent("", "  ")
+
+			return e.Encode(c.Schema())
+		},
+	}
+}
+
+// loadConfig reads the config file, returning the configuration along with the settings of the
+// file which don't exist.
+func loadConfig() (c.Config, []string, error) {
+	if err := checkConfigFile(); err != nil {
+		return c.Config{}, nil, err
+	}
+
+	viper.SetConfigType(ConfigFileType)
+	viper.SetConfigName(ConfigFileName)
+
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(envKeyReplacer)
+	viper.AutomaticEnv()
+
+	if err := viper.ReadInConfig(); err != nil {
+		return c.Config{}, nil, err
+	}
+
+	var md mapstructure.Metadata
+
+	next, err := unmarshalConfig(&md)
+
+	return next, md.Unused, err
+}
+
+// unmarshalConfig returns the configuration read by viper, filling md with the keys decoded if
+// it is not nil.
+func unmarshalConfig(md *mapstructure.Metadata) (c.Config, error) {
+	var next c.Config
+
+	err := viper.Unmarshal(&next, viper.DecodeHook(c.DecodeHook()), func(dc *mapstructure.DecoderConfig) {
+		dc.Metadata = md
+	})
+
+	return next, err
+}
+
+// validateConfig returns the c.Problems of next, along with the unknown settings.
+func validateConfig(next c.Config, unknown []string) error {
+	var problems, others c.Problems
+
+	sort.Strings(unknown)
+	for _, each := range unknown {
+		problems = append(problems, c.Problem{Key: each, Err: c.ErrUnknownSetting})
+	}
+
+	if err := next.Validate(); errors.As(err, &others) {
+		problems = append(problems, others...)
+	} else if err != nil {
+		return err
+	}
+
+	if err := checkServer(next.Server); err != nil {
+		problems = append(problems, c.Problem{Key: "server", Err: err})
+	}
+
+	if next.Trash.Retention < 0 {
+		problems = append(problems, c.Problem{Key: "trash.retention", Err: ErrNegativeRetention})
+	}
+
+	if _, _, err := utils.CheckLimits(next.Pagination.LimitDefault, next.Pagination.LimitMax); err != nil {
+		problems = append(problems, c.Problem{Key: "pagination", Err: err})
+	}
+
+	return problems.Err()
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	var (
+		problems c.Problems
+		next     = c.Config{
+			Database:   c.Database{Host: "localhost", Name: "home"},
+			Trash:      c.Trash{Retention: -1},
+			Pagination: c.Pagination{LimitDefault: 20, LimitMax: 10},
+		}
+	)
+
+	err := validateConfig(next, []string{"trash.retension", "logger.levl"})
+
+	require.ErrorAs(t, err, &problems)
+	assert.Equal(t, []c.Problem{
+		{Key: "logger.levl", Err: c.ErrUnknownSetting},
+		{Key: "trash.retension", Err: c.ErrUnknownSetting},
+		{Key: "trash.retention", Err: ErrNegativeRetention},
+	}, []c.Problem(problems[:3]))
+	assert.ErrorIs(t, err, problems[3].Err)
+	assert.Equal(t, "pagination", problems[3].Key)
+}
+
+func TestLoadConfig(t *testing.T) {
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	backupConfigFile(t, pwd, ConfigFileName+"."+ConfigFileType)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	require.NoError(t, os.WriteFile(filepath.Join(pwd, ConfigFileName+"."+ConfigFileType), []byte(
+		"db: host=localhost\nlogger:\n  console_appender:\n    level: WARN\n  colour: true\n",
+	), 0600))
+
+	got, unknown, err := loadConfig()
+
+	assert.NoError(t, err)
+	assert.Equal(t, c.LoggerLevel(c.WarnLevel), got.Logger.ConsoleAppender.LoggerFileLevel)
+	assert.Equal(t, c.Database{DSN: "host=localhost"}, got.Database)
+	assert.Equal(t, []string{"logger.colour"}, unknown)
+}
+
+func TestLoadDatabaseConfig(t *testing.T) {
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	backupConfigFile(t, pwd, ConfigFileName+"."+ConfigFileType)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	addConfigFlags(&cobra.Command{Use: "go-home"})
+	t.Setenv(EnvName("db.password"), "secret")
+
+	require.NoError(t, os.WriteFile(filepath.Join(pwd, ConfigFileName+"."+ConfigFileType), []byte(
+		"db:\n  host: localhost\n  dbname: home\n  pool:\n    conn_max_lifetime: 1h\n",
+	), 0600))
+
+	got, unknown, err := loadConfig()
+
+	assert.NoError(t, err)
+	assert.Empty(t, unknown)
+	assert.Equal(t, c.Database{
+		Host:     "localhost",
+		Password: "secret",
+		Name:     "home",
+		Pool:     c.Pool{ConnMaxLifetime: time.Hour},
+		Retry:    defaultConfig().Database.Retry,
+	}, got.Database)
+}
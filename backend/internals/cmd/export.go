@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/MrTimeout/go-home/backend/api/food/bulk"
+	"github.com/spf13/cobra"
+)
+
+const outputFlag = "output"
+
+// NewExportCmd returns the command which exports the catalog which is not in the trash.
+func NewExportCmd() *cobra.Command {
+	var output, format string
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the categories, subcategories, units and varieties as CSV, JSON or YAML",
+		Long: "Export the categories, subcategories, units and varieties which are not in the trash as CSV, JSON or YAML. " +
+			"The file can be imported back with go-home import. The format is taken from the extension of the output " +
+			"file unless it is given, being JSON when writing to the standard output.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := fileFormat(output, format)
+			if err != nil {
+				return err
+			}
+
+			records, err := bulk.Export(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			out, err := openOutput(cmd, output)
+			if err != nil {
+				return err
+			}
+
+			if err = bulk.Encode(f, out, records); err != nil {
+				out.Close()
+				return err
+			}
+
+			return out.Close()
+		},
+	}
+
+	exportCmd.Flags().StringVarP(&output, outputFlag, "o", stdio, "file to write, - for the standard output")
+	exportCmd.Flags().StringVar(&format, formatFlag, "", "format of the file: csv, json or yaml")
+
+	return exportCmd
+}
+
+// fileFormat returns the format named format or, if it is empty, the one of the extension of
+// the file name, which is JSON for the standard input and output.
+func fileFormat(name, format string) (bulk.Format, error) {
+	switch {
+	case format != "":
+		return bulk.ParseFormat(format)
+	case name == stdio:
+		return bulk.JSON, nil
+	}
+
+	return bulk.FormatOf(name)
+}
+
+// openInput opens the file name, or the standard input of cmd if it is stdio.
+func openInput(cmd *cobra.Command, name string) (io.ReadCloser, error) {
+	if name == stdio {
+		return io.NopCloser(cmd.InOrStdin()), nil
+	}
+
+	return os.Open(name)
+}
+
+// openOutput creates the file name, or returns the standard output of cmd if it is stdio.
+func openOutput(cmd *cobra.Command, name string) (io.WriteCloser, error) {
+	if name == stdio {
+		return nopWriteCloser{cmd.OutOrStdout()}, nil
+	}
+
+	return os.Create(name)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/api/health"
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// EnvPrefix is the prefix of the environment variables which override the settings, like
+	// GO_HOME_LOGGER_CONSOLE_APPENDER_LEVEL for logger.console_appender.level.
+	EnvPrefix = "GO_HOME"
+
+	// keyAnnotation is the annotation of the flags of the subcommands with the key of the setting
+	// they override, as a shorthand of the flag of the root command.
+	keyAnnotation = "go-home/key"
+
+	// DefaultRetryAttempts is how many times connecting to the database is tried when it is not configured.
+	DefaultRetryAttempts = 10
+	// DefaultInitialBackoff is the first wait between the attempts to connect to the database when it is not configured.
+	DefaultInitialBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the longest wait between the attempts to connect to the database when it is not configured.
+	DefaultMaxBackoff = 10 * time.Second
+	// DefaultMetricsPath is the route of the metrics when it is not configured.
+	DefaultMetricsPath = "/metrics"
+	// DefaultSlowThreshold is the latency from which the queries are logged as slow when it is not configured.
+	DefaultSlowThreshold = 200 * time.Millisecond
+)
+
+// envKeyReplacer turns the keys of the settings into the suffix of their environment variable.
+var envKeyReplacer = strings.NewReplacer(".", "_")
+
+// defaultConfig returns the configuration used when neither the flags, the environment nor the
+// config file set a setting.
+func defaultConfig() c.Config {
+	return c.Config{
+		Database: c.Database{
+			Retry: c.Retry{Attempts: DefaultRetryAttempts, InitialBackoff: DefaultInitialBackoff, MaxBackoff: DefaultMaxBackoff},
+		},
+		Trash:   c.Trash{Retention: DefaultRetention},
+		Metrics: c.Metrics{Path: DefaultMetricsPath, HTTP: true, Database: true, Runtime: true},
+		AccessLog: c.AccessLog{
+			Enabled:       true,
+			SampleRate:    1,
+			ExcludePaths:  []string{health.LivenessPath, health.ReadinessPath, health.StartupPath, DefaultMetricsPath},
+			RedactHeaders: []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key"},
+		},
+		SQLLog: c.SQLLog{Level: "warn", SlowThreshold: DefaultSlowThreshold, RedactParams: true, IgnoreRecordNotFound: true},
+		Server: c.Server{
+			Address:         DefaultAddress,
+			MaxHeaderBytes:  http.DefaultMaxHeaderBytes,
+			ShutdownTimeout: DefaultShutdownTimeout,
+			TLS:             c.TLS{MinVersion: c.TLS12},
+		},
+	}
+}
+
+// addConfigFlags adds a persistent flag to cmd for each setting of c.Config, named after its key
+// like --logger-console-appender-level, and binds it to viper. The lists of objects, like
+// logger.file_appenders, are set as YAML or JSON.
+func addConfigFlags(cmd *cobra.Command) {
+	flags, defaults := cmd.PersistentFlags(), defaultConfig()
+
+	walkConfig(reflect.ValueOf(&defaults).Elem(), "", func(key string, field reflect.Value) {
+		name, usage := FlagName(key), "overrides "+key+", also set by "+EnvName(key)
+
+		switch value := field.Addr().Interface().(type) {
+		case *time.Duration:
+			flags.DurationVar(value, name, *value, usage)
+		case pflag.Value:
+			flags.Var(value, name, usage)
+		case *string:
+			flags.StringVar(value, name, *value, usage)
+		case *bool:
+			flags.BoolVar(value, name, *value, usage)
+		case *int:
+			flags.IntVar(value, name, *value, usage)
+		case *float64:
+			flags.Float64Var(value, name, *value, usage)
+		case *[]string:
+			flags.StringSliceVar(value, name, *value, usage)
+		default:
+			flags.String(name, "", usage+", as YAML or JSON")
+		}
+
+		viper.BindPFlag(key, flags.Lookup(name)) //nolint:errcheck
+	})
+}
+
+// walkConfig calls fn with the key and the value of each setting of v, which is a c.Config or
+// any of its sections.
+func walkConfig(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("mapstructure")
+		if name == "" {
+			continue
+		}
+
+		if field := v.Field(i); field.Kind() == reflect.Struct {
+			walkConfig(field, prefix+name+".", fn)
+		} else {
+			fn(prefix+name, field)
+		}
+	}
+}
+
+// FlagName returns the name of the flag of the root command which overrides the setting key.
+func FlagName(key string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(key)
+}
+
+// EnvName returns the environment variable which overrides the setting key.
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// bindFlags makes the flags of a subcommand shorthands of the flags of the root command which
+// override the settings of keys, indexed by flag name.
+func bindFlags(flags *pflag.FlagSet, keys map[string]string) {
+	for flag, key := range keys {
+		flags.SetAnnotation(flag, keyAnnotation, []string{key}) //nolint:errcheck
+	}
+}
+
+// applyShorthands copies the flags of cmd which were set and are shorthands of the flags of the
+// root command, so viper reads them with the precedence of the flags.
+func applyShorthands(cmd *cobra.Command) error {
+	var err error
+
+	cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		keys := flag.Annotations[keyAnnotation]
+		if !flag.Changed || len(keys) == 0 || err != nil {
+			return
+		}
+
+		root := cmd.Root().PersistentFlags().Lookup(FlagName(keys[0]))
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			err = root.Value.(pflag.SliceValue).Replace(slice.GetSlice())
+		} else {
+			err = root.Value.Set(flag.Value.String())
+		}
+
+		root.Changed = true
+	})
+
+	return err
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagAndEnvNames(t *testing.T) {
+	assert.Equal(t, "logger-console-appender-level", FlagName("logger.console_appender.level"))
+	assert.Equal(t, "GO_HOME_LOGGER_CONSOLE_APPENDER_LEVEL", EnvName("logger.console_appender.level"))
+}
+
+func TestConfigOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ConfigFileName+"."+ConfigFileType)
+	require.NoError(t, os.WriteFile(file, []byte(
+		"server:\n  address: :1000\n  read_timeout: 1s\nlogger:\n  console_appender:\n    level: debug\n",
+	), 0600))
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	rootCmd := &cobra.Command{Use: "go-home"}
+	addConfigFlags(rootCmd)
+
+	serveCmd := &cobra.Command{Use: "serve"}
+	serveCmd.Flags().String(addressFlag, DefaultAddress, "")
+	bindFlags(serveCmd.Flags(), map[string]string{addressFlag: "server.address"})
+	rootCmd.AddCommand(serveCmd)
+
+	t.Setenv(EnvName("server.address"), ":2000")
+	t.Setenv(EnvName("server.read_timeout"), "2s")
+	t.Setenv(EnvName("logger.file_appenders"), `[{"file": "go-home.log", "level": "WARN"}]`)
+
+	require.NoError(t, rootCmd.PersistentFlags().Set(FlagName("server.read_timeout"), "3s"))
+	require.NoError(t, serveCmd.Flags().Set(addressFlag, ":3000"))
+	require.NoError(t, applyShorthands(serveCmd))
+
+	viper.SetConfigFile(file)
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(envKeyReplacer)
+	viper.AutomaticEnv()
+	require.NoError(t, viper.ReadInConfig())
+
+	got, err := unmarshalConfig(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, ":3000", got.Server.Address, "flags are over the environment")
+	assert.Equal(t, 3*time.Second, got.Server.ReadTimeout, "flags are over the environment")
+	assert.Equal(t, c.LoggerLevel(c.DebugLevel), got.Logger.ConsoleAppender.LoggerFileLevel, "the file is over the defaults")
+	assert.Equal(t, []c.FileLoggerAppender{{LoggerFileName: "go-home.log", LoggerFileLevel: c.WarnLevel}}, got.Logger.FileAppenders)
+	assert.Equal(t, DefaultShutdownTimeout, got.Server.ShutdownTimeout)
+}
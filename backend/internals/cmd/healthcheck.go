@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/api/health"
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/MrTimeout/go-home/backend/internals/migrations"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultHealthcheckTimeout is how long the healthcheck command waits for the response.
+	DefaultHealthcheckTimeout = 5 * time.Second
+
+	probeFlag      = "probe"
+	timeoutFlag    = "timeout"
+	clientCertFlag = "cert"
+	clientKeyFlag  = "key"
+)
+
+var (
+	// ErrUnhealthy is used when the probe requested by the healthcheck command is down.
+	ErrUnhealthy = errors.New("API is not healthy")
+	// ErrUnknownProbe is used when the probe of the healthcheck command doesn't exist.
+	ErrUnknownProbe = fmt.Errorf("probe must be %s, %s or %s", health.Liveness, health.Readiness, health.Startup)
+	// ErrConfigNotLoaded is used by the readiness check until the configuration is applied.
+	ErrConfigNotLoaded = errors.New("configuration is not loaded")
+)
+
+// NewHealthcheckCmd returns the command which requests a probe of the API served by this host, so
+// it can be used as the HEALTHCHECK of a container without curl. It fails if the probe is down.
+func NewHealthcheckCmd() *cobra.Command {
+	var (
+		probe             string
+		timeout           time.Duration
+		certFile, keyFile string
+	)
+
+	healthcheckCmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Check the health of the API served by this host",
+		Long: "Request the probe of the API served at server.address, over TLS if server.tls is configured, and fail " +
+			"if it is down. The certificate of the server is not verified, as it may not be issued for localhost. The " +
+			"database is not connected to.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return applyShorthands(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, ok := health.Paths[health.Probe(probe)]
+			if !ok {
+				return fmt.Errorf("%w, got %q", ErrUnknownProbe, probe)
+			}
+
+			next, _, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			client, err := healthcheckClient(next.Server.TLS, certFile, keyFile)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return healthcheck(ctx, client, healthcheckURL(next.Server, path), cmd.OutOrStdout())
+		},
+	}
+
+	flags := healthcheckCmd.Flags()
+	flags.StringVar(&probe, probeFlag, string(health.Readiness), "probe to request: "+
+		string(health.Liveness)+", "+string(health.Readiness)+" or "+string(health.Startup))
+	flags.DurationVar(&timeout, timeoutFlag, DefaultHealthcheckTimeout, "how long the response is waited for")
+	flags.StringVar(&certFile, clientCertFlag, "", "PEM client certificate, required if server.tls.client_ca_file is set")
+	flags.StringVar(&keyFile, clientKeyFlag, "", "PEM private key of the client certificate")
+
+	return healthcheckCmd
+}
+
+// healthcheck requests u, copying the report to w, and returns ErrUnhealthy if it is not 200 OK.
+func healthcheck(ctx context.Context, client *http.Client, u string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if _, err = io.Copy(w, res.Body); err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrUnhealthy, res.Status)
+	}
+
+	return nil
+}
+
+// healthcheckURL returns the URL of path in the API served at s.Address, using localhost when it
+// listens on all the interfaces.
+func healthcheckURL(s c.Server, path string) string {
+	host, port, err := net.SplitHostPort(s.Address)
+	if err != nil {
+		host, port = s.Address, ""
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	u := url.URL{Scheme: "http", Host: host, Path: path}
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	}
+
+	if s.TLS.Enabled() {
+		u.Scheme = "https"
+	}
+
+	return u.String()
+}
+
+// healthcheckClient returns the client of the healthcheck command, which presents the client
+// certificate of certFile and keyFile if they are set.
+func healthcheckClient(t c.TLS, certFile, keyFile string) (*http.Client, error) {
+	if !t.Enabled() {
+		return http.DefaultClient, nil
+	}
+
+	config := &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: config}}, nil
+}
+
+// registerHealthChecks registers the checks of the probes of the API: it is ready when the
+// configuration is loaded, the database can be reached and the migrations were applied, unless
+// migrations.allow_pending is enabled. The migrations are only read, without taking their lock.
+// It is started once MarkStarted is called.
+func registerHealthChecks() {
+	health.Register(health.Readiness, health.Checker{Name: "config", Check: checkConfigLoaded})
+	health.Register(health.Readiness, health.Checker{Name: "database", Timeout: health.PingTimeout, Check: c.PingDB})
+	health.Register(health.Readiness, health.Checker{Name: "migrations", Check: checkPendingMigrations})
+	health.Register(health.Startup, health.Checker{Name: "started", Check: health.CheckStarted})
+}
+
+func checkConfigLoaded(context.Context) error {
+	if !c.Loaded() {
+		return ErrConfigNotLoaded
+	}
+
+	return nil
+}
+
+func checkPendingMigrations(ctx context.Context) error {
+	if cfg.Migrations.AllowPending {
+		return nil
+	}
+
+	m, err := newMigrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	pending, err := m.ReadPending()
+	if err != nil {
+		return err
+	} else if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending", migrations.ErrPendingMigrations, len(pending))
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthcheckURL(t *testing.T) {
+	for _, each := range []struct {
+		input c.Server
+		want  string
+	}{
+		{input: c.Server{Address: ":8080"}, want: "http://localhost:8080/readyz"},
+		{input: c.Server{Address: "0.0.0.0:8080"}, want: "http://localhost:8080/readyz"},
+		{input: c.Server{Address: "[::]:8443", TLS: c.TLS{CertFile: "server.pem", KeyFile: "server-key.pem"}}, want: "https://localhost:8443/readyz"},
+		{input: c.Server{Address: "192.168.1.10:8080"}, want: "http://192.168.1.10:8080/readyz"},
+	} {
+		t.Run(each.input.Address, func(t *testing.T) {
+			assert.Equal(t, each.want, healthcheckURL(each.input, "/readyz"))
+		})
+	}
+}
+
+func TestHealthcheck(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"status":"up"}`)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+
+	assert.NoError(t, healthcheck(context.Background(), srv.Client(), srv.URL, &out))
+	assert.Equal(t, `{"status":"up"}`, out.String())
+
+	status = http.StatusServiceUnavailable
+	assert.ErrorIs(t, healthcheck(context.Background(), srv.Client(), srv.URL, &out), ErrUnhealthy)
+}
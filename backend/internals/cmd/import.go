@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/MrTimeout/go-home/backend/api/food/bulk"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	modeFlag   = "mode"
+	formatFlag = "format"
+
+	// stdio is the file name which means the standard input or output.
+	stdio = "-"
+)
+
+// NewImportCmd returns the command which imports the catalog of a file in a single transaction.
+func NewImportCmd() *cobra.Command {
+	var mode, format string
+
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import the categories, subcategories, units and varieties of a CSV, JSON or YAML file",
+		Long: "Import the categories, subcategories, units and varieties of a CSV, JSON or YAML file in a single " +
+			"transaction, so nothing is saved if any of them fails. The format is taken from the extension of FILE " +
+			"unless it is given. FILE can be - to read the standard input.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := utils.ParseMode(mode, utils.Insert)
+			if err != nil {
+				return err
+			}
+
+			f, err := fileFormat(args[0], format)
+			if err != nil {
+				return err
+			}
+
+			in, err := openInput(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			records, err := bulk.Decode(f, in)
+			if err != nil {
+				return err
+			}
+
+			report, err := bulk.Import(cmd.Context(), records, m)
+			if err != nil {
+				return err
+			}
+
+			printReport(cmd.OutOrStdout(), report)
+
+			return nil
+		},
+	}
+
+	importCmd.Flags().StringVar(&mode, modeFlag, string(utils.Insert),
+		"how the resources which already exist are handled: insert fails, upsert updates them and "+
+			"replace updates them and moves the ones missing from the file to the trash")
+	importCmd.Flags().StringVar(&format, formatFlag, "", "format of the file: csv, json or yaml")
+
+	return importCmd
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/MrTimeout/go-home/backend/internals/migrations"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMigrationsDir is where the migrate create command writes the new migrations,
+	// relative to the backend directory.
+	DefaultMigrationsDir = "internals/migrations/sql"
+
+	stepsFlag   = "steps"
+	dirFlag     = "dir"
+	versionFlag = "version"
+)
+
+// NewMigrateCmd returns the command which manages the migrations of the database schema.
+func NewMigrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the migrations of the database schema",
+		Long: "Manage the migrations of the database schema. The migrations are embedded in the binary " +
+			"and the ones applied are stored in the " + migrations.Table + " table. The databases created " +
+			"before the migrations, by the AutoMigrate of previous versions, must be baselined once with " +
+			"migrate baseline before migrate up.",
+	}
+
+	migrateCmd.AddCommand(newMigrateUpCmd(), newMigrateDownCmd(), newMigrateStatusCmd(), newMigrateCreateCmd(), newMigrateBaselineCmd())
+
+	return migrateCmd
+}
+
+func newMigrateUpCmd() *cobra.Command {
+	var steps int
+
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply the pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := newMigrator(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			applied, err := m.Up(steps)
+			for _, each := range applied {
+				fmt.Fprintf(cmd.OutOrStdout(), "applied %s\n", each)
+			}
+
+			return err
+		},
+	}
+
+	upCmd.Flags().IntVar(&steps, stepsFlag, 0, "amount of migrations to apply, all of them if it is 0")
+
+	return upCmd
+}
+
+func newMigrateDownCmd() *cobra.Command {
+	var steps int
+
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Roll back the last migrations applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := newMigrator(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			rolledBack, err := m.Down(steps)
+			for _, each := range rolledBack {
+				fmt.Fprintf(cmd.OutOrStdout(), "rolled back %s\n", each)
+			}
+
+			return err
+		},
+	}
+
+	downCmd.Flags().IntVar(&steps, stepsFlag, 1, "amount of migrations to roll back")
+
+	return downCmd
+}
+
+func newMigrateStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the migrations and whether they were applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := newMigrator(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			status, err := m.Status()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+			for _, each := range status {
+				appliedAt := "pending"
+				if each.AppliedAt != nil {
+					appliedAt = each.AppliedAt.Format(time.RFC3339)
+				}
+
+				fmt.Fprintf(w, "%d\t%s\t%s\n", each.Version, each.Name, appliedAt)
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+func newMigrateCreateCmd() *cobra.Command {
+	var dir string
+
+	createCmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create the empty up and down files of a new migration",
+		Long: "Create the empty up and down files of a new migration, using the current time as version. " +
+			"The binary must be built again to embed it. The config file is not read and the database is not connected to.",
+		Args: cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return applyShorthands(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, err := migrations.Create(dir, args[0], time.Now())
+			for _, each := range paths {
+				fmt.Fprintf(cmd.OutOrStdout(), "created %s\n", each)
+			}
+
+			return err
+		},
+	}
+
+	createCmd.Flags().StringVar(&dir, dirFlag, DefaultMigrationsDir, "directory of the migration files")
+
+	return createCmd
+}
+
+func newMigrateBaselineCmd() *cobra.Command {
+	var version int64
+
+	baselineCmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Record the migrations which the existing schema already has as applied",
+		Long: "Record the migrations up to --version as applied without running them, for the databases " +
+			"whose tables were created before the migrations, by the AutoMigrate of previous versions. By default, " +
+			"only the first migration, which creates the tables, is recorded. Run migrate up afterwards to apply the rest, " +
+			"which adds what the AutoMigrate didn't create, like the trash and the varieties.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := newMigrator(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			recorded, err := m.Baseline(version)
+			for _, each := range recorded {
+				fmt.Fprintf(cmd.OutOrStdout(), "baselined %s\n", each)
+			}
+
+			return err
+		},
+	}
+
+	baselineCmd.Flags().Int64Var(&version, versionFlag, 0, "version of the last migration to record, the first one if it is 0")
+
+	return baselineCmd
+}
+
+// CheckMigrations is called before serving the API. It applies the pending migrations if
+// migrations.auto is enabled. Otherwise, it fails if there are pending migrations, unless
+// migrations.allow_pending is enabled.
+func CheckMigrations(ctx context.Context) error {
+	m, err := newMigrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Migrations.Auto {
+		applied, err := m.Up(0)
+		for _, each := range applied {
+			c.Info("migration applied", zap.Stringer("migration", each))
+		}
+
+		return err
+	}
+
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	} else if len(pending) == 0 {
+		return nil
+	} else if !cfg.Migrations.AllowPending {
+		return fmt.Errorf("%w: %d pending", migrations.ErrPendingMigrations, len(pending))
+	}
+
+	for _, each := range pending {
+		c.Warn("migration pending", zap.Stringer("migration", each))
+	}
+
+	return nil
+}
+
+func newMigrator(ctx context.Context) (*migrations.Migrator, error) {
+	embedded, err := migrations.Embedded()
+	if err != nil {
+		return nil, err
+	}
+
+	return migrations.New(c.GetInstance(ctx), embedded), nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	"github.com/MrTimeout/go-home/backend/api/food/trash"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultRetention is how long the resources stay in the trash when it is not configured.
+	DefaultRetention = 30 * 24 * time.Hour
+
+	retentionFlag = "retention"
+	retentionKey  = "trash.retention"
+)
+
+// ErrNegativeRetention is used when the retention of the trash is negative.
+var ErrNegativeRetention = errors.New("retention of the trash cannot be negative")
+
+// NewPurgeCmd returns the command which hard deletes the resources that have been in the
+// trash for longer than the retention, taken from the flag or from trash.retention.
+func NewPurgeCmd() *cobra.Command {
+	purgeCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete for good the resources which have been in the trash longer than the retention",
+		Long: "Delete for good the categories, subcategories, units and varieties which have been in the trash " +
+			"longer than the retention. They can't be restored after being purged.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Trash.Retention < 0 {
+				return ErrNegativeRetention
+			}
+
+			rows, err := trash.Purge(cmd.Context(), cfg.Trash.Retention)
+			if err != nil {
+				return err
+			}
+
+			for _, each := range ca.Hierarchy {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %d purged\n", each.Kind, rows[each.Kind])
+			}
+
+			return nil
+		},
+	}
+
+	purgeCmd.Flags().Duration(retentionFlag, DefaultRetention, "how long the resources stay in the trash before being purged")
+	bindFlags(purgeCmd.Flags(), map[string]string{retentionFlag: retentionKey})
+
+	return purgeCmd
+}
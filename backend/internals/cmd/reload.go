@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// reloadMu serializes the reloads, which can be triggered by the file watcher and by the API at once.
+var reloadMu sync.Mutex
+
+// applyConfig applies the settings of next which can change at runtime: the logger, the pool of
+// connections of the database, the pagination, the CORS, the rate limit and the access log. Nothing
+// is applied if any of them is not valid.
+func applyConfig(next c.Config) error {
+	if err := next.Check(); err != nil {
+		return err
+	}
+
+	if _, _, err := utils.CheckLimits(next.Pagination.LimitDefault, next.Pagination.LimitMax); err != nil {
+		return err
+	}
+
+	if err := c.ConfigurePool(next.Database.Pool); err != nil {
+		return err
+	}
+
+	utils.SetLimits(next.Pagination.LimitDefault, next.Pagination.LimitMax) //nolint:errcheck
+	c.ConfigureLogger(next.Logger)
+	c.SetCurrent(next)
+
+	return nil
+}
+
+// ReloadConfig reads the config file again and applies the settings which can change at runtime.
+// If the file is not valid, the current configuration is kept. It returns the settings which
+// changed but need a restart, like the server or the database.
+func ReloadConfig() ([]string, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	next, err := unmarshalConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	current := c.Current()
+
+	if err := applyConfig(next); err != nil {
+		return nil, err
+	}
+
+	restart := restartRequired(current, next)
+	if len(restart) > 0 {
+		c.Warn("configuration changed, but these settings need a restart", zap.Strings("settings", restart))
+	}
+
+	c.Info("configuration reloaded", zap.String("file", viper.ConfigFileUsed()))
+
+	return restart, nil
+}
+
+// watchConfig reloads the configuration whenever the config file changes, logging the errors.
+func watchConfig() {
+	viper.OnConfigChange(func(fsnotify.Event) {
+		if _, err := ReloadConfig(); err != nil {
+			c.Error("reloading the configuration, keeping the current one", zap.Error(err))
+		}
+	})
+
+	viper.WatchConfig()
+}
+
+// restartRequired returns the keys of the settings which differ between current and next and
+// can't be changed at runtime.
+func restartRequired(current, next c.Config) []string {
+	var result []string
+
+	current.Database.Pool, next.Database.Pool = c.Pool{}, c.Pool{}
+
+	for key, changed := range map[string]bool{
+		"db":         current.Database != next.Database,
+		"metrics":    current.Metrics != next.Metrics,
+		"migrations": current.Migrations != next.Migrations,
+		"server":     !reflect.DeepEqual(current.Server, next.Server),
+		"trash":      current.Trash != next.Trash,
+	} {
+		if changed {
+			result = append(result, key)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
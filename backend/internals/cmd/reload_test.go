@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadConfig(t *testing.T) {
+	file, previous := filepath.Join(t.TempDir(), ConfigFileName+"."+ConfigFileType), cfg
+	viper.Reset()
+	viper.SetConfigFile(file)
+	cfg = c.Config{}
+	c.SetCurrent(c.Config{})
+	t.Cleanup(func() {
+		viper.Reset()
+		c.SetCurrent(c.Config{})
+		cfg = previous
+	})
+
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+	}
+
+	t.Run("applies the new settings", func(t *testing.T) {
+		write("db: other\nrate_limit:\n  requests_per_second: 5\n")
+
+		restart, err := ReloadConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"db"}, restart)
+		assert.Equal(t, 5.0, c.Current().RateLimit.RequestsPerSecond)
+	})
+
+	t.Run("settings which need a restart are reported once", func(t *testing.T) {
+		write("db: other\nrate_limit:\n  requests_per_second: 6\n")
+
+		restart, err := ReloadConfig()
+
+		assert.NoError(t, err)
+		assert.Empty(t, restart)
+		assert.Equal(t, 6.0, c.Current().RateLimit.RequestsPerSecond)
+	})
+
+	t.Run("keeps the current settings if the new ones are not valid", func(t *testing.T) {
+		write("rate_limit:\n  requests_per_second: 1\ncors:\n  allowed_origins: [home.lan]\n")
+
+		_, err := ReloadConfig()
+
+		assert.ErrorIs(t, err, c.ErrInvalidOrigin)
+		assert.Equal(t, 6.0, c.Current().RateLimit.RequestsPerSecond)
+	})
+
+	t.Run("keeps the current settings if a log file is not writable", func(t *testing.T) {
+		write("rate_limit:\n  requests_per_second: 1\nlogger:\n  file_appenders:\n  - file: " + t.TempDir() + "\n")
+
+		_, err := ReloadConfig()
+
+		assert.ErrorIs(t, err, c.ErrLogFileNotWritable)
+		assert.Equal(t, 6.0, c.Current().RateLimit.RequestsPerSecond)
+	})
+
+	t.Run("keeps the current settings if the file is malformed", func(t *testing.T) {
+		write("rate_limit: [")
+
+		_, err := ReloadConfig()
+
+		assert.Error(t, err)
+		assert.Equal(t, 6.0, c.Current().RateLimit.RequestsPerSecond)
+	})
+}
+
+func TestRestartRequired(t *testing.T) {
+	current := c.Config{Database: c.Database{Name: "home"}, Server: c.Server{Address: ":8080"}}
+	next := c.Config{Database: c.Database{Name: "home"}, Server: c.Server{Address: ":8443"}, RateLimit: c.RateLimit{RequestsPerSecond: 1}}
+
+	assert.Equal(t, []string{"server"}, restartRequired(current, next))
+	assert.Empty(t, restartRequired(current, current))
+
+	next = current
+	next.Database.Pool = c.Pool{MaxOpenConns: 10}
+	assert.Empty(t, restartRequired(current, next))
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/MrTimeout/go-home/backend/api/food/bulk"
+	ca "github.com/MrTimeout/go-home/backend/api/food/category"
+	"github.com/MrTimeout/go-home/backend/api/food/seed"
+	"github.com/spf13/cobra"
+)
+
+const fileFlag = "file"
+
+// NewSeedCmd returns the command which loads the catalog of a seed file, or the one embedded in
+// the binary, upserting each resource by name.
+func NewSeedCmd() *cobra.Command {
+	var file string
+
+	seedCmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Load the reference catalog of categories, subcategories, units and varieties",
+		Long: "Load the reference catalog of categories, subcategories, units and varieties. Each resource is created, " +
+			"or updated if there is already one with the same name, so it can be run several times. " +
+			"The catalog embedded in the binary is loaded unless a CSV, JSON or YAML file is given.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			records, err := seed.Default()
+			if file != "" {
+				records, err = bulk.ReadFile(file)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			report, err := seed.Seed(cmd.Context(), records)
+			if err != nil {
+				return err
+			}
+
+			printReport(cmd.OutOrStdout(), report)
+
+			return nil
+		},
+	}
+
+	seedCmd.Flags().StringVar(&file, fileFlag, "", "CSV, JSON or YAML seed file to load instead of the embedded one")
+
+	return seedCmd
+}
+
+// printReport writes the counts of each kind of resource of report.
+func printReport(w io.Writer, report bulk.Report) {
+	for i, each := range ca.Hierarchy {
+		fmt.Fprintf(w, "%s: %s\n", each.Kind, report.Of(i))
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MrTimeout/go-home/backend/api"
+	"github.com/MrTimeout/go-home/backend/api/health"
+	"github.com/MrTimeout/go-home/backend/api/utils"
+	"github.com/MrTimeout/go-home/backend/internals/certs"
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/MrTimeout/go-home/backend/internals/metrics"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultAddress is the address the API listens on when it is not configured.
+	DefaultAddress = ":8080"
+	// DefaultShutdownTimeout is how long the in-flight requests are waited for when it is not configured.
+	DefaultShutdownTimeout = 15 * time.Second
+
+	addressFlag         = "address"
+	readTimeoutFlag     = "read-timeout"
+	writeTimeoutFlag    = "write-timeout"
+	idleTimeoutFlag     = "idle-timeout"
+	maxHeaderBytesFlag  = "max-header-bytes"
+	shutdownTimeoutFlag = "shutdown-timeout"
+	tlsCertFlag         = "tls-cert"
+	tlsKeyFlag          = "tls-key"
+	tlsClientCAFlag     = "tls-client-ca"
+	tlsMinVersionFlag   = "tls-min-version"
+)
+
+var (
+	// ErrNegativeTimeout is used when any of the timeouts of the server is negative.
+	ErrNegativeTimeout = errors.New("timeouts of the server cannot be negative")
+	// ErrNegativeMaxHeaderBytes is used when the maximum size of the headers is negative.
+	ErrNegativeMaxHeaderBytes = errors.New("max header bytes of the server cannot be negative")
+)
+
+// NewServeCmd returns the command which serves the API until it receives SIGINT or SIGTERM. Then,
+// it stops accepting connections, waits for the in-flight requests and closes the database. The API
+// is served over TLS when server.tls is configured, reloading the certificates when they change, and
+// the configuration is reloaded when the config file changes. The stats of the database are logged
+// every db.stats_interval, the probes of the orchestrator are served at /healthz, /readyz and
+// /startupz, and the Prometheus metrics at metrics.path if metrics.enabled is set.
+func NewServeCmd() *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the go-home API",
+		Long: "Serve the go-home API until SIGINT or SIGTERM is received. Then, the in-flight requests are waited " +
+			"for up to the shutdown timeout before closing the connections to the database.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := CheckMigrations(ctx); err != nil {
+				return err
+			}
+
+			if err := utils.RegisterValidations(); err != nil {
+				return err
+			}
+
+			if err := metrics.Configure(cfg.Metrics, c.GetInstance(ctx)); err != nil {
+				return err
+			}
+
+			watchConfig()
+			registerHealthChecks()
+
+			if cfg.Database.StatsInterval > 0 {
+				go c.LogDBStats(ctx, cfg.Database.StatsInterval)
+			}
+
+			srv := newServer(cfg.Server)
+
+			if cfg.Server.TLS.Enabled() {
+				reloader, err := certs.NewReloader(cfg.Server.TLS)
+				if err != nil {
+					return err
+				}
+
+				go func() {
+					if err := reloader.Watch(ctx); err != nil {
+						c.Warn("watching the tls files, they won't be reloaded", zap.Error(err))
+					}
+				}()
+
+				srv.TLSConfig = reloader.TLSConfig()
+			}
+
+			health.MarkStarted()
+
+			return serve(ctx, srv, cfg.Server.ShutdownTimeout)
+		},
+	}
+
+	flags := serveCmd.Flags()
+	flags.String(addressFlag, DefaultAddress, "TCP address to listen on")
+	flags.Duration(readTimeoutFlag, 0, "maximum duration for reading an entire request, 0 means no timeout")
+	flags.Duration(writeTimeoutFlag, 0, "maximum duration for writing the response, 0 means no timeout")
+	flags.Duration(idleTimeoutFlag, 0, "maximum duration to wait for the next request on keep-alive connections, "+
+		"the read timeout is used if it is 0")
+	flags.Int(maxHeaderBytesFlag, http.DefaultMaxHeaderBytes, "maximum size of the request headers")
+	flags.Duration(shutdownTimeoutFlag, DefaultShutdownTimeout, "how long the in-flight requests are waited for when stopping")
+	flags.String(tlsCertFlag, "", "PEM certificate of the server, TLS is enabled along with --tls-key")
+	flags.String(tlsKeyFlag, "", "PEM private key of the certificate of the server")
+	flags.String(tlsClientCAFlag, "", "PEM certificates which sign the client certificates, required if it is set")
+	flags.String(tlsMinVersionFlag, c.TLS12, "minimum TLS version: "+c.TLS12+" or "+c.TLS13)
+
+	bindFlags(flags, map[string]string{
+		addressFlag:         "server.address",
+		readTimeoutFlag:     "server.read_timeout",
+		writeTimeoutFlag:    "server.write_timeout",
+		idleTimeoutFlag:     "server.idle_timeout",
+		maxHeaderBytesFlag:  "server.max_header_bytes",
+		shutdownTimeoutFlag: "server.shutdown_timeout",
+		tlsCertFlag:         "server.tls.cert_file",
+		tlsKeyFlag:          "server.tls.key_file",
+		tlsClientCAFlag:     "server.tls.client_ca_file",
+		tlsMinVersionFlag:   "server.tls.min_version",
+	})
+
+	return serveCmd
+}
+
+func checkServer(s c.Server) error {
+	if s.ReadTimeout < 0 || s.WriteTimeout < 0 || s.IdleTimeout < 0 || s.ShutdownTimeout < 0 {
+		return ErrNegativeTimeout
+	} else if s.MaxHeaderBytes < 0 {
+		return ErrNegativeMaxHeaderBytes
+	}
+
+	return nil
+}
+
+func newServer(s c.Server) *http.Server {
+	return &http.Server{
+		Addr:           s.Address,
+		Handler:        api.NewRouter(ReloadConfig),
+		ReadTimeout:    s.ReadTimeout,
+		WriteTimeout:   s.WriteTimeout,
+		IdleTimeout:    s.IdleTimeout,
+		MaxHeaderBytes: s.MaxHeaderBytes,
+	}
+}
+
+// serve runs srv until ctx is done, then it shuts srv down waiting for the in-flight requests up to
+// shutdownTimeout, and closes the database. A second signal while shutting down stops right away.
+func serve(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	errs := make(chan error, 1)
+
+	go func() {
+		if srv.TLSConfig != nil {
+			errs <- srv.ListenAndServeTLS("", "")
+		} else {
+			errs <- srv.ListenAndServe()
+		}
+	}()
+
+	c.Info("serving the API", zap.String("address", srv.Addr), zap.Bool("tls", srv.TLSConfig != nil))
+
+	select {
+	case err := <-errs:
+		if cErr := c.CloseDB(); cErr != nil {
+			c.Error("closing the database", zap.Error(cErr))
+		}
+
+		return err
+	case <-ctx.Done():
+	}
+
+	c.Info("shutting down the API", zap.Duration("timeout", shutdownTimeout))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	shutdownCtx, stop := signal.NotifyContext(shutdownCtx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := srv.Shutdown(shutdownCtx)
+	if cErr := c.CloseDB(); err == nil {
+		err = cErr
+	}
+
+	c.Info("API stopped")
+
+	return err
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	c "github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckServer(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       c.Server
+		want        error
+	}{
+		{
+			description: "zero values",
+		},
+		{
+			description: "negative write timeout",
+			input:       c.Server{WriteTimeout: -time.Second},
+			want:        ErrNegativeTimeout,
+		},
+		{
+			description: "negative max header bytes",
+			input:       c.Server{MaxHeaderBytes: -1},
+			want:        ErrNegativeMaxHeaderBytes,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.ErrorIs(t, checkServer(each.input), each.want)
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	c.ConfigureLogger(c.Logger{})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan error)
+		go func() {
+			done <- serve(ctx, &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}, time.Second)
+		}()
+
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("serve did not stop")
+		}
+	})
+
+	t.Run("fails when it can't listen", func(t *testing.T) {
+		err := serve(context.Background(), &http.Server{Addr: "127.0.0.1:-1"}, time.Second)
+
+		assert.Error(t, err)
+	})
+}
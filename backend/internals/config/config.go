@@ -26,8 +26,180 @@ var (
 // Config is the main structure which we are going to use to store the configuration of the application.
 // Here we have the logger configuration.
 type Config struct {
-	Database string `json:"db" yaml:"db" mapstructure:"db"`
-	Logger   Logger `json:"logger" yaml:"logger" mapstructure:"logger"`
+	Database   Database   `json:"db" yaml:"db" mapstructure:"db"`
+	Logger     Logger     `json:"logger" yaml:"logger" mapstructure:"logger"`
+	Trash      Trash      `json:"trash" yaml:"trash" mapstructure:"trash"`
+	Migrations Migrations `json:"migrations" yaml:"migrations" mapstructure:"migrations"`
+	Server     Server     `json:"server" yaml:"server" mapstructure:"server"`
+	Pagination Pagination `json:"pagination" yaml:"pagination" mapstructure:"pagination"`
+	CORS       CORS       `json:"cors" yaml:"cors" mapstructure:"cors"`
+	RateLimit  RateLimit  `json:"rate_limit" yaml:"rate_limit" mapstructure:"rate_limit"`
+	Metrics    Metrics    `json:"metrics" yaml:"metrics" mapstructure:"metrics"`
+	AccessLog  AccessLog  `json:"access_log" yaml:"access_log" mapstructure:"access_log"`
+	SQLLog     SQLLog     `json:"sql_log" yaml:"sql_log" mapstructure:"sql_log"`
+}
+
+// Database is the configuration of the connection to PostgreSQL, from which the connection string
+// is built. The password can be kept out of the config file, in the environment or in a file like
+// the Docker and Kubernetes secrets.
+type Database struct {
+	// DSN is the whole connection string, used instead of the other connection settings. It is what
+	// the db setting used to be, so the config files written before keep working.
+	DSN  string `json:"dsn" yaml:"dsn" mapstructure:"dsn"`
+	Host string `json:"host" yaml:"host" mapstructure:"host"`
+	Port int    `json:"port" yaml:"port" mapstructure:"port"`
+	User string `json:"user" yaml:"user" mapstructure:"user"`
+	// Password is better set by the environment or by PasswordFile than written in the config file.
+	Password string `json:"password" yaml:"password" mapstructure:"password"`
+	// PasswordFile is the file the password is read from, ignoring the trailing new line.
+	PasswordFile string `json:"password_file" yaml:"password_file" mapstructure:"password_file"`
+	Name         string `json:"dbname" yaml:"dbname" mapstructure:"dbname"`
+	// SSLMode is disable, allow, prefer, require, verify-ca or verify-full, being prefer if empty.
+	SSLMode  string `json:"sslmode" yaml:"sslmode" mapstructure:"sslmode"`
+	TimeZone string `json:"timezone" yaml:"timezone" mapstructure:"timezone"`
+	// Pool is the configuration of the pool of connections.
+	Pool Pool `json:"pool" yaml:"pool" mapstructure:"pool"`
+	// Retry is how connecting is retried when the database is not up yet.
+	Retry Retry `json:"retry" yaml:"retry" mapstructure:"retry"`
+	// StatsInterval is how often the stats of the pool of connections are logged, never if it is 0.
+	StatsInterval time.Duration `json:"stats_interval" yaml:"stats_interval" mapstructure:"stats_interval"`
+}
+
+// Retry is how connecting to the database is retried on start, waiting an exponential backoff
+// between the attempts: InitialBackoff, twice it, and so on up to MaxBackoff.
+type Retry struct {
+	// Attempts is how many times connecting is tried, only once if it is 0.
+	Attempts       int           `json:"attempts" yaml:"attempts" mapstructure:"attempts"`
+	InitialBackoff time.Duration `json:"initial_backoff" yaml:"initial_backoff" mapstructure:"initial_backoff"`
+	// MaxBackoff is the longest wait between two attempts, unlimited if it is 0.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" mapstructure:"max_backoff"`
+}
+
+// Pool is the configuration of the pool of connections to the database, as in sql.DB. The zero
+// values mean the defaults of sql.DB. It can be changed at runtime.
+type Pool struct {
+	// MaxOpenConns is the maximum amount of connections open, unlimited if it is 0.
+	MaxOpenConns int `json:"max_open_conns" yaml:"max_open_conns" mapstructure:"max_open_conns"`
+	// MaxIdleConns is the maximum amount of idle connections kept, 2 if it is 0.
+	MaxIdleConns int `json:"max_idle_conns" yaml:"max_idle_conns" mapstructure:"max_idle_conns"`
+	// ConnMaxLifetime is how long a connection is reused at most, forever if it is 0.
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime" mapstructure:"conn_max_lifetime"`
+	// ConnMaxIdleTime is how long a connection is kept idle at most, forever if it is 0.
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time" mapstructure:"conn_max_idle_time"`
+}
+
+// Pagination is the size of the pages of the lists of the API. The zero values mean
+// the defaults of the API.
+type Pagination struct {
+	// LimitDefault is the size of the pages when the request doesn't set the limit.
+	LimitDefault int `json:"limit_default" yaml:"limit_default" mapstructure:"limit_default"`
+	// LimitMax is the maximum limit allowed. Bigger limits are replaced by LimitDefault.
+	LimitMax int `json:"limit_max" yaml:"limit_max" mapstructure:"limit_max"`
+}
+
+// CORS is the configuration of the cross-origin requests, which are not allowed by default.
+type CORS struct {
+	// AllowedOrigins are the origins allowed, like https://home.lan, or * to allow any of them.
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins" mapstructure:"allowed_origins"`
+	// AllowedMethods are the methods allowed in the requests. All the methods of the API if it is empty.
+	AllowedMethods []string `json:"allowed_methods" yaml:"allowed_methods" mapstructure:"allowed_methods"`
+	// AllowedHeaders are the headers allowed in the requests. Accept, Accept-Language and Content-Type if it is empty.
+	AllowedHeaders []string `json:"allowed_headers" yaml:"allowed_headers" mapstructure:"allowed_headers"`
+	// ExposedHeaders are the headers of the responses the clients can read. X-Total-Count, Link and X-Request-ID if it is empty.
+	ExposedHeaders []string `json:"exposed_headers" yaml:"exposed_headers" mapstructure:"exposed_headers"`
+	// AllowCredentials allows the requests with cookies or client certificates. It can't be used along with *.
+	AllowCredentials bool `json:"allow_credentials" yaml:"allow_credentials" mapstructure:"allow_credentials"`
+	// MaxAge is how long the browsers can cache the preflight responses.
+	MaxAge time.Duration `json:"max_age" yaml:"max_age" mapstructure:"max_age"`
+}
+
+// RateLimit is the amount of requests allowed for each client IP, which is unlimited by default.
+type RateLimit struct {
+	// RequestsPerSecond is the rate the requests are allowed at. 0 disables the limit.
+	RequestsPerSecond float64 `json:"requests_per_second" yaml:"requests_per_second" mapstructure:"requests_per_second"`
+	// Burst is the amount of requests allowed at once. It is the rate rounded up if it is 0.
+	Burst int `json:"burst" yaml:"burst" mapstructure:"burst"`
+}
+
+// AccessLog is the configuration of the log of the requests, which can be changed at runtime.
+type AccessLog struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// SampleRate is the fraction of the successful requests logged, between 0 and 1. The requests
+	// which fail are always logged.
+	SampleRate float64 `json:"sample_rate" yaml:"sample_rate" mapstructure:"sample_rate"`
+	// ExcludePaths are the paths whose requests are not logged, like the probes.
+	ExcludePaths []string `json:"exclude_paths" yaml:"exclude_paths" mapstructure:"exclude_paths"`
+	// Headers enables logging the headers of the requests.
+	Headers bool `json:"headers" yaml:"headers" mapstructure:"headers"`
+	// RedactHeaders are the headers whose values are never logged, case insensitive.
+	RedactHeaders []string `json:"redact_headers" yaml:"redact_headers" mapstructure:"redact_headers"`
+}
+
+// SQLLog is the configuration of the log of the queries of the database, which can be changed at runtime.
+type SQLLog struct {
+	// Level is silent, error, warn or info, being warn if empty. The queries which fail are logged from
+	// error level, the slow ones from warn level and the rest, at debug level, from info level.
+	Level string `json:"level" yaml:"level" mapstructure:"level"`
+	// SlowThreshold is the latency from which the queries are logged as slow. 0 disables it.
+	SlowThreshold time.Duration `json:"slow_threshold" yaml:"slow_threshold" mapstructure:"slow_threshold"`
+	// RedactParams replaces the string values of the queries logged, like the names and descriptions.
+	RedactParams bool `json:"redact_params" yaml:"redact_params" mapstructure:"redact_params"`
+	// IgnoreRecordNotFound skips logging the queries which fail because they didn't find any record.
+	IgnoreRecordNotFound bool `json:"ignore_record_not_found" yaml:"ignore_record_not_found" mapstructure:"ignore_record_not_found"`
+}
+
+// Metrics is the configuration of the Prometheus metrics of the API, which are disabled by default.
+// Each kind of metrics can be disabled on its own.
+type Metrics struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Path is the route the metrics are served at.
+	Path string `json:"path" yaml:"path" mapstructure:"path"`
+	// HTTP enables the count, latency and size of the responses of each route.
+	HTTP bool `json:"http" yaml:"http" mapstructure:"http"`
+	// Database enables the latency and errors of the queries of each table, and the pool of connections.
+	Database bool `json:"db" yaml:"db" mapstructure:"db"`
+	// Runtime enables the metrics of the Go runtime and the process.
+	Runtime bool `json:"runtime" yaml:"runtime" mapstructure:"runtime"`
+}
+
+// Server is the configuration of the HTTP server of the API. The timeouts which are zero
+// mean no timeout, as in http.Server.
+type Server struct {
+	// Address is the TCP address to listen on, like :8080.
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// ReadTimeout is the maximum duration for reading an entire request, including the body.
+	ReadTimeout time.Duration `json:"read_timeout" yaml:"read_timeout" mapstructure:"read_timeout"`
+	// WriteTimeout is the maximum duration before timing out writes of the response.
+	WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout" mapstructure:"write_timeout"`
+	// IdleTimeout is the maximum amount of time to wait for the next request when keep-alives are enabled.
+	IdleTimeout time.Duration `json:"idle_timeout" yaml:"idle_timeout" mapstructure:"idle_timeout"`
+	// MaxHeaderBytes is the maximum size of the request headers.
+	MaxHeaderBytes int `json:"max_header_bytes" yaml:"max_header_bytes" mapstructure:"max_header_bytes"`
+	// ShutdownTimeout is how long the in-flight requests are waited for when the server is stopped.
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout" mapstructure:"shutdown_timeout"`
+	// TrustedProxies are the IPs or CIDRs of the proxies whose X-Forwarded-For and X-Real-IP headers
+	// are trusted to find the IP of the clients, used by the rate limit and the access log. No proxy is
+	// trusted by default, so the IP of the clients is the one of the connection and they can't spoof it.
+	TrustedProxies []string `json:"trusted_proxies" yaml:"trusted_proxies" mapstructure:"trusted_proxies"`
+	// TLS is the configuration of the encryption, which is disabled by default.
+	TLS TLS `json:"tls" yaml:"tls" mapstructure:"tls"`
+}
+
+// Migrations is the configuration of the migrations of the database schema when the API starts.
+// By default, the API refuses to start while there are pending migrations.
+type Migrations struct {
+	// Auto applies the pending migrations when the API starts.
+	Auto bool `json:"auto" yaml:"auto" mapstructure:"auto"`
+	// AllowPending starts the API even if there are pending migrations.
+	AllowPending bool `json:"allow_pending" yaml:"allow_pending" mapstructure:"allow_pending"`
+}
+
+// Trash is the configuration of the resources deleted through the API, which are kept
+// in the trash until they are purged.
+type Trash struct {
+	// Retention is how long the resources stay in the trash before the purge command
+	// deletes them for good.
+	Retention time.Duration `json:"retention" yaml:"retention" mapstructure:"retention"`
 }
 
 // Logger is where all zap logger stuff will go
@@ -61,6 +233,13 @@ func NewLogger(console bool, loggerLevel LoggerLevel, loggerFileNames ...string)
 
 // Tee create core loggers to log into them
 func (l Logger) Tee() *zap.Logger {
+	logger, _ := l.tee()
+	return logger
+}
+
+// tee creates the logger like Tee and returns the files opened by the file appenders, to close
+// them once the logger is not used anymore.
+func (l Logger) tee() (*zap.Logger, []*os.File) {
 	var cfg zapcore.EncoderConfig
 
 	if l.Production {
@@ -69,14 +248,23 @@ func (l Logger) Tee() *zap.Logger {
 		cfg = zap.NewDevelopmentEncoderConfig()
 	}
 
+	var files []*os.File
+
 	cores := make([]zapcore.Core, len(l.FileAppenders))
-	for i := range l.FileAppenders {
-		cores[i] = l.FileAppenders[i].core(cfg)
+	for i, each := range l.FileAppenders {
+		logfile, err := each.open()
+		if err != nil {
+			cores[i] = zapcore.NewNopCore()
+			continue
+		}
+
+		files = append(files, logfile)
+		cores[i] = each.coreOf(cfg, logfile)
 	}
 
 	cores = append(cores, l.ConsoleAppender.core(cfg))
 
-	return zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+	return zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), files
 }
 
 // Appender describes a standard appender to the zap logger.
@@ -123,13 +311,23 @@ func NewFileLoggerAppender(loggerLevel LoggerLevel, fileName string, dateTimeFor
 }
 
 func (fla FileLoggerAppender) core(config zapcore.EncoderConfig) zapcore.Core {
-	if logfile, err := os.OpenFile(fla.LoggerFileName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0660); err == nil {
-		config.EncodeTime = fla.DateTimeFormat.ToZapTimeEncoder()
-		return zapcore.NewCore(zapcore.NewJSONEncoder(config), zapcore.AddSync(logfile), fla.LoggerFileLevel.ToZapLevel())
+	if logfile, err := fla.open(); err == nil {
+		return fla.coreOf(config, logfile)
 	}
 	return zapcore.NewNopCore()
 }
 
+// open opens the file of the appender to append to it, creating it if it doesn't exist.
+func (fla FileLoggerAppender) open() (*os.File, error) {
+	return os.OpenFile(fla.LoggerFileName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0660)
+}
+
+// coreOf returns the core which writes the entries into logfile.
+func (fla FileLoggerAppender) coreOf(config zapcore.EncoderConfig, logfile *os.File) zapcore.Core {
+	config.EncodeTime = fla.DateTimeFormat.ToZapTimeEncoder()
+	return zapcore.NewCore(zapcore.NewJSONEncoder(config), zapcore.AddSync(logfile), fla.LoggerFileLevel.ToZapLevel())
+}
+
 // DateTimeFormat is just a string type, that contains all the date time formats allowed by zap library.
 type DateTimeFormat string
 
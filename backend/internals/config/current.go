@@ -0,0 +1,27 @@
+package config
+
+import (
+	"sync/atomic"
+)
+
+var current atomic.Pointer[Config]
+
+// SetCurrent makes cfg the configuration returned by Current.
+func SetCurrent(cfg Config) {
+	current.Store(&cfg)
+}
+
+// Current returns the configuration in use, which changes when it is reloaded. It is the zero
+// Config until SetCurrent is called.
+func Current() Config {
+	if cfg := current.Load(); cfg != nil {
+		return *cfg
+	}
+
+	return Config{}
+}
+
+// Loaded returns whether the configuration was set by SetCurrent.
+func Loaded() bool {
+	return current.Load() != nil
+}
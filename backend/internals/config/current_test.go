@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigCheck(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       Config
+		want        error
+	}{
+		{
+			description: "zero values",
+		},
+		{
+			description: "cors origins",
+			input:       Config{CORS: CORS{AllowedOrigins: []string{"https://home.lan", "http://localhost:3000/"}, AllowCredentials: true}},
+		},
+		{
+			description: "invalid logger level",
+			input:       Config{Logger: Logger{FileAppenders: []FileLoggerAppender{{LoggerFileLevel: "verbose"}}}},
+			want:        ErrLoggerLevelNotAllowed,
+		},
+		{
+			description: "log file not writable",
+			input:       Config{Logger: Logger{FileAppenders: []FileLoggerAppender{{LoggerFileName: t.TempDir()}}}},
+			want:        ErrLogFileNotWritable,
+		},
+		{
+			description: "cors origin with path",
+			input:       Config{CORS: CORS{AllowedOrigins: []string{"https://home.lan/app"}}},
+			want:        ErrInvalidOrigin,
+		},
+		{
+			description: "cors credentials for any origin",
+			input:       Config{CORS: CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true}},
+			want:        ErrCredentialsWithAnyOrigin,
+		},
+		{
+			description: "negative rate limit",
+			input:       Config{RateLimit: RateLimit{RequestsPerSecond: -1}},
+			want:        ErrNegativeRateLimit,
+		},
+		{
+			description: "negative pagination",
+			input:       Config{Pagination: Pagination{LimitMax: -1}},
+			want:        ErrNegativePagination,
+		},
+		{
+			description: "invalid sql log level",
+			input:       Config{SQLLog: SQLLog{Level: "debug"}},
+			want:        ErrInvalidSQLLogLevel,
+		},
+		{
+			description: "negative slow threshold",
+			input:       Config{SQLLog: SQLLog{SlowThreshold: -time.Second}},
+			want:        ErrNegativeSlowThreshold,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.ErrorIs(t, each.input.Check(), each.want)
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	t.Cleanup(func() {
+		current.Store(nil)
+	})
+
+	assert.Equal(t, Config{}, Current())
+
+	SetCurrent(Config{RateLimit: RateLimit{RequestsPerSecond: 2}})
+
+	assert.Equal(t, 2.0, Current().RateLimit.RequestsPerSecond)
+}
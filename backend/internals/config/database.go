@@ -2,33 +2,304 @@ package config
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// redactedPassword replaces the password in the connection strings logged or printed.
+const redactedPassword = "xxxxx"
+
+// ErrDBNotConfigured is used when the database is used before ConfigureDB.
+var ErrDBNotConfigured = errors.New("database is not configured")
+
 var (
 	db     *gorm.DB
 	dbOnce sync.Once
+	// errDB is the error of the first call to ConfigureDB, returned by all the calls.
+	errDB error
+
+	// passwordSetting matches the password of a connection string like host=host password=secret.
+	passwordSetting = regexp.MustCompile(`(password\s*=\s*)('(\\.|[^'\\])*'|[^\s]+)`)
+	// sslModes are the values allowed by sslmode, the empty one being prefer.
+	sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
 )
 
-// ConfigureDB is going to configure the database with the string passed as a parameter
-func ConfigureDB(connStr string) {
+// txKey is the key of the transaction stored in the context by WithTransaction.
+type txKey struct{}
+
+// ConfigureDB is going to configure the database and its pool of connections with the
+// configuration passed as a parameter. Connecting is retried as configured by d.Retry, until
+// ctx is done. The connection string is logged without the password. Only the first call
+// configures the database, and the later ones return its error.
+func ConfigureDB(ctx context.Context, d Database) error {
 	dbOnce.Do(func() {
-		var err error
+		errDB = configureDB(ctx, d)
+	})
+
+	return errDB
+}
+
+func configureDB(ctx context.Context, d Database) error {
+	connStr, err := d.ConnString()
+	if err != nil {
+		return err
+	}
+
+	if db, err = open(ctx, connStr, d.Retry); err != nil {
+		return err
+	}
+
+	if err = ConfigurePool(d.Pool); err != nil {
+		return err
+	}
+
+	Info("database configured", zap.String("dsn", d.Redacted()))
+
+	return nil
+}
+
+// open connects to the database of connStr, retrying with an exponential backoff.
+func open(ctx context.Context, connStr string, r Retry) (*gorm.DB, error) {
+	backoff := r.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		conn, err := gorm.Open(postgres.Open(connStr), &gorm.Config{Logger: newGormLogger()})
+		if err == nil {
+			return conn, nil
+		} else if conn != nil {
+			if sqlDB, err := conn.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}
+
+		if attempt >= r.Attempts {
+			return nil, err
+		}
+
+		Warn("connecting to the database, retrying", zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+			backoff = r.MaxBackoff
+		}
+	}
+}
+
+// ConfigurePool applies p to the pool of connections of the database, if it is configured.
+func ConfigurePool(p Pool) error {
+	if db == nil {
+		return nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	sqlDB.SetMaxOpenConns(p.MaxOpenConns)
+	if p.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	sqlDB.SetConnMaxLifetime(p.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+
+	return nil
+}
+
+// PingDB returns an error if the database can't be reached.
+func PingDB(ctx context.Context) error {
+	if db == nil {
+		return ErrDBNotConfigured
+	}
+
+	sqlDB, err := GetInstance(ctx).DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
+
+// DBStats returns the stats of the pool of connections of the database, which are empty if it
+// is not configured.
+func DBStats() sql.DBStats {
+	if db == nil {
+		return sql.DBStats{}
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+
+	return sqlDB.Stats()
+}
+
+// LogDBStats logs the stats of the pool of connections of the database every interval, until
+// ctx is done.
+func LogDBStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := DBStats()
+			Info("database stats",
+				zap.Int("open", stats.OpenConnections),
+				zap.Int("in_use", stats.InUse),
+				zap.Int("idle", stats.Idle),
+				zap.Int64("wait_count", stats.WaitCount),
+				zap.Duration("wait_duration", stats.WaitDuration),
+				zap.Int64("max_idle_closed", stats.MaxIdleClosed),
+				zap.Int64("max_idle_time_closed", stats.MaxIdleTimeClosed),
+				zap.Int64("max_lifetime_closed", stats.MaxLifetimeClosed),
+			)
+		}
+	}
+}
 
-		db, err = gorm.Open(postgres.Open(connStr), &gorm.Config{})
+// ConnString returns DSN if it is set. Otherwise, it returns the connection string built from the
+// connection settings which are set, reading the password from PasswordFile if it is set. The
+// settings which are not set take the defaults of PostgreSQL, like the port 5432.
+func (d Database) ConnString() (string, error) {
+	if d.DSN != "" {
+		return d.DSN, nil
+	}
+
+	password := d.Password
+	if d.PasswordFile != "" {
+		b, err := os.ReadFile(d.PasswordFile)
 		if err != nil {
-			panic(err)
+			return "", err
 		}
-	})
+
+		password = strings.TrimRight(string(b), "\r\n")
+	}
+
+	var settings []string
+	for _, each := range [...]struct{ key, value string }{
+		{"host", d.Host},
+		{"port", portOf(d.Port)},
+		{"user", d.User},
+		{"password", password},
+		{"dbname", d.Name},
+		{"sslmode", d.SSLMode},
+		{"TimeZone", d.TimeZone},
+	} {
+		if each.value != "" {
+			settings = append(settings, each.key+"="+quoteSetting(each.value))
+		}
+	}
+
+	return strings.Join(settings, " "), nil
+}
+
+// Redacted returns the connection string with the password replaced, so it can be logged. The
+// password file is not read.
+func (d Database) Redacted() string {
+	if d.DSN == "" {
+		if d.Password != "" || d.PasswordFile != "" {
+			d.Password, d.PasswordFile = redactedPassword, ""
+		}
+
+		connStr, _ := d.ConnString()
+		return connStr
+	}
+
+	if u, err := url.Parse(d.DSN); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		if query := u.Query(); query.Has("password") {
+			query.Set("password", redactedPassword)
+			u.RawQuery = query.Encode()
+		}
+
+		return u.Redacted()
+	}
+
+	return passwordSetting.ReplaceAllString(d.DSN, "${1}"+redactedPassword)
+}
+
+// Redacted returns a copy of c without the password of the database, so it can be printed.
+func (c Config) Redacted() Config {
+	if c.Database.DSN != "" {
+		c.Database.DSN = c.Database.Redacted()
+	}
+
+	if c.Database.Password != "" {
+		c.Database.Password = redactedPassword
+	}
+
+	return c
+}
+
+// portOf returns port as a setting of a connection string, which is empty if it is not set.
+func portOf(port int) string {
+	if port == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(port)
+}
+
+// quoteSetting quotes value if it is empty or it has spaces, quotes or backslashes, as required
+// by the connection strings like host=host dbname=dbname.
+func quoteSetting(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r'\\") {
+		return value
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
 }
 
+// GetInstance returns the database bound to ctx, or the transaction of ctx if it was set by
+// WithTransaction. The transactions opened inside of it become savepoints.
 func GetInstance(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
 	return db.WithContext(ctx)
 }
 
+// WithTransaction returns a copy of ctx whose GetInstance is tx, so the repository functions
+// called with it run inside of the same transaction.
+func WithTransaction(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// CloseDB closes the connection pool of the database, if it was configured.
+func CloseDB() error {
+	if db == nil {
+		return nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
+
+// GetDrySession returns a session of GetInstance which builds the statements without running them.
 func GetDrySession(ctx context.Context) *gorm.DB {
-	return db.Session(&gorm.Session{DryRun: true}).WithContext(ctx)
+	return GetInstance(ctx).Session(&gorm.Session{DryRun: true})
 }
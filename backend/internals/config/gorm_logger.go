@@ -0,0 +1,112 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// redactedParam replaces the string values of the queries logged when sql_log.redact_params is set.
+const redactedParam = "'xxxxx'"
+
+var (
+	// sqlLogLevels are the values allowed by sql_log.level, the empty one being warn.
+	sqlLogLevels = []string{"silent", "error", "warn", "info"}
+	// stringLiteral matches the string values of the queries explained by gorm, like 'it''s'.
+	stringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+)
+
+// gormLogger writes the logs of gorm through the logger of the context of each query, returned by
+// LoggerFrom, so they carry the ID of the request which made the query. It follows the SQLLog of
+// the current configuration, so it can be changed at runtime.
+type gormLogger struct {
+	// level overrides the level of the configuration if it is set by LogMode, as db.Debug() does.
+	level gormlogger.LogLevel
+}
+
+// newGormLogger returns the logger of gorm, which logs the queries as set by SQLLog.
+func newGormLogger() gormlogger.Interface {
+	return gormLogger{}
+}
+
+// LogMode returns a copy of l which logs at level.
+func (l gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	l.level = level
+	return l
+}
+
+// Info logs the message at info level.
+func (l gormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if level, _ := l.settings(); level >= gormlogger.Info {
+		LoggerFrom(ctx).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn logs the message at warn level.
+func (l gormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if level, _ := l.settings(); level >= gormlogger.Warn {
+		LoggerFrom(ctx).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error logs the message at error level.
+func (l gormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if level, _ := l.settings(); level >= gormlogger.Error {
+		LoggerFrom(ctx).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace logs the query run since begin: at error level if it failed, at warn level if it was slow
+// and at debug level otherwise, if the level of l allows it.
+func (l gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	level, cfg := l.settings()
+	elapsed := time.Since(begin)
+
+	var (
+		log func(string, ...zap.Field)
+		msg string
+	)
+
+	switch {
+	case err != nil && level >= gormlogger.Error && !(cfg.IgnoreRecordNotFound && errors.Is(err, gorm.ErrRecordNotFound)):
+		log, msg = LoggerFrom(ctx).With(zap.Error(err)).Error, "query failed"
+	case cfg.SlowThreshold != 0 && elapsed > cfg.SlowThreshold && level >= gormlogger.Warn:
+		log, msg = LoggerFrom(ctx).Warn, "slow query"
+	case level >= gormlogger.Info:
+		log, msg = LoggerFrom(ctx).Debug, "query"
+	default:
+		return
+	}
+
+	sql, rows := fc()
+	if cfg.RedactParams {
+		sql = stringLiteral.ReplaceAllLiteralString(sql, redactedParam)
+	}
+
+	log(msg, zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("elapsed", elapsed))
+}
+
+// settings returns the level of l and the SQLLog of the current configuration.
+func (l gormLogger) settings() (gormlogger.LogLevel, SQLLog) {
+	cfg := Current().SQLLog
+	if l.level != 0 {
+		return l.level, cfg
+	}
+
+	switch cfg.Level {
+	case "silent":
+		return gormlogger.Silent, cfg
+	case "error":
+		return gormlogger.Error, cfg
+	case "info":
+		return gormlogger.Info, cfg
+	default:
+		return gormlogger.Warn, cfg
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "go-home.log")
+	ConfigureLogger(Logger{
+		Production:    true,
+		FileAppenders: []FileLoggerAppender{NewFileLoggerAppender(DebugLevel, file, RFC3339)},
+	})
+	t.Cleanup(func() {
+		current.Store(nil)
+		ConfigureLogger(Logger{})
+	})
+
+	SetCurrent(Config{SQLLog: SQLLog{SlowThreshold: 200 * time.Millisecond, RedactParams: true, IgnoreRecordNotFound: true}})
+
+	ctx := WithRequestID(context.Background(), "42")
+	l := newGormLogger()
+	query := func(sql string) func() (string, int64) {
+		return func() (string, int64) { return sql, 1 }
+	}
+
+	l.Trace(ctx, time.Now(), query("SELECT 1"), nil)
+	l.Trace(ctx, time.Now(), query("SELECT 2"), errors.New("boom"))
+	l.Trace(ctx, time.Now(), query("SELECT 3"), gorm.ErrRecordNotFound)
+	l.Trace(ctx, time.Now().Add(-time.Second), query("SELECT 4 WHERE name = 'it''s' AND id = 7"), nil)
+	l.LogMode(gormlogger.Info).Trace(ctx, time.Now(), query("SELECT 5"), nil)
+
+	SetCurrent(Config{SQLLog: SQLLog{Level: "silent"}})
+	l.Trace(ctx, time.Now(), query("SELECT 6"), errors.New("boom"))
+
+	SetCurrent(Config{SQLLog: SQLLog{Level: "info"}})
+	l.Trace(ctx, time.Now(), query("SELECT 7 WHERE name = 'fruits'"), nil)
+
+	fileContains(t, file, `"msg":"query failed","request_id":"42","error":"boom","sql":"SELECT 2"`)
+	fileContains(t, file, `"msg":"slow query","request_id":"42","sql":"SELECT 4 WHERE name = 'xxxxx' AND id = 7"`)
+	fileContains(t, file, `"msg":"query","request_id":"42","sql":"SELECT 5"`)
+	fileContains(t, file, `"msg":"query","request_id":"42","sql":"SELECT 7 WHERE name = 'fruits'"`)
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	for _, each := range []string{"SELECT 1", "SELECT 3", "SELECT 6", "it''s"} {
+		assert.NotContains(t, string(b), each)
+	}
+}
@@ -1,47 +1,106 @@
 package config
 
 import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+
 	"go.uber.org/zap"
 )
 
-var logger *zap.Logger
+var (
+	logger atomic.Pointer[zap.Logger]
+
+	// filesMu serializes ConfigureLogger and guards files, the files written by the current logger.
+	filesMu sync.Mutex
+	files   []*os.File
+)
+
+type (
+	// loggerKey is the key of the logger stored in the context by WithRequestID.
+	loggerKey struct{}
+	// requestIDKey is the key of the request ID stored in the context by WithRequestID.
+	requestIDKey struct{}
+)
 
-// ConfigureLogger configures the zap logger from a Config structure
+// ConfigureLogger configures the zap logger from a Config structure. It can be called again to
+// replace the logger while it is being used, flushing the previous one and closing its files.
 func ConfigureLogger(cfg Logger) {
-	logger = cfg.Tee()
+	filesMu.Lock()
+	defer filesMu.Unlock()
+
+	next, nextFiles := cfg.tee()
+	if previous := logger.Swap(next); previous != nil {
+		previous.Sync() //nolint:errcheck
+	}
+
+	for _, each := range files {
+		each.Close()
+	}
+
+	files = nextFiles
+}
+
+// WithRequestID returns a copy of ctx with the ID of the request and a logger which adds it to
+// every message, returned by LoggerFrom.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	if l := logger.Load(); l != nil {
+		ctx = context.WithValue(ctx, loggerKey{}, l.With(zap.String("request_id", id)))
+	}
+
+	return ctx
+}
+
+// RequestID returns the ID of the request stored in ctx by WithRequestID, which is empty if
+// there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// LoggerFrom returns the logger stored in ctx by WithRequestID or, if there is none, the logger
+// used by Debug, Info and the rest.
+func LoggerFrom(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return l
+	}
+
+	return logger.Load()
 }
 
 // Debug will log a zap.Logger debug message
 func Debug(msg string, fields ...zap.Field) {
-	logger.Debug(msg, fields...)
+	logger.Load().Debug(msg, fields...)
 }
 
 // Info will log a zap.Logger info message
 func Info(msg string, fields ...zap.Field) {
-	logger.Info(msg, fields...)
+	logger.Load().Info(msg, fields...)
 }
 
 // Warn will log a zap.Logger warn message
 func Warn(msg string, fields ...zap.Field) {
-	logger.Warn(msg, fields...)
+	logger.Load().Warn(msg, fields...)
 }
 
 // Error will log a zap.Logger error message
 func Error(msg string, fields ...zap.Field) {
-	logger.Error(msg, fields...)
+	logger.Load().Error(msg, fields...)
 }
 
 // DPanic will log a zap.Logger dpanic message
 func DPanic(msg string, fields ...zap.Field) {
-	logger.DPanic(msg, fields...)
+	logger.Load().DPanic(msg, fields...)
 }
 
 // Panic will log a zap.Logger panic message
 func Panic(msg string, fields ...zap.Field) {
-	logger.Panic(msg, fields...)
+	logger.Load().Panic(msg, fields...)
 }
 
 // Fatal will log a zap.Logger fatal message
 func Fatal(msg string, fields ...zap.Field) {
-	logger.Fatal(msg, fields...)
+	logger.Load().Fatal(msg, fields...)
 }
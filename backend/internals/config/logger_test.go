@@ -2,9 +2,11 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -83,3 +85,24 @@ func TestLoggerFuncs(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureLoggerClosesFiles(t *testing.T) {
+	dir := t.TempDir()
+	configure := func(name string) []*os.File {
+		ConfigureLogger(Logger{FileAppenders: []FileLoggerAppender{NewFileLoggerAppender(DebugLevel, filepath.Join(dir, name), RFC3339)}})
+		return files
+	}
+	t.Cleanup(func() { ConfigureLogger(Logger{}) })
+
+	first := configure("first.log")
+	require.Len(t, first, 1)
+
+	second := configure("second.log")
+	require.Len(t, second, 1)
+
+	assert.ErrorIs(t, first[0].Close(), os.ErrClosed)
+	assert.NoError(t, second[0].Sync())
+
+	Info("written after the reload")
+	fileContains(t, second[0].Name(), "written after the reload")
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaDialect is the version of JSON Schema used by Schema.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	// durationPattern matches the durations of time.ParseDuration, like 1h30m.
+	durationPattern = `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+	loggerLevels    = []string{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, DPanicLevel, PanicLevel, FatalLevel}
+	dateTimeFormats = []string{
+		ANSIC, UnixDate, RubyDate, RFC822, RFC822Z, RFC850, RFC1123, RFC1123Z, RFC3339, RFC3339Nano,
+		Kitchen, Stamp, StampMilli, StampMicro, StampNano,
+	}
+)
+
+// Schema returns the JSON Schema of the config file, generated from Config, so the editors can
+// autocomplete and check it. The settings which don't exist are not allowed.
+func Schema() map[string]any {
+	schema := schemaOf(reflect.TypeOf(Config{}))
+	schema["$schema"] = SchemaDialect
+	schema["title"] = "go-home configuration"
+
+	return schema
+}
+
+func schemaOf(t reflect.Type) map[string]any {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	case reflect.TypeOf(LoggerLevel("")):
+		return map[string]any{"type": "string", "enum": caseVariants(loggerLevels)}
+	case reflect.TypeOf(DateTimeFormat("")):
+		return map[string]any{"type": "string", "enum": caseVariants(dateTimeFormats)}
+	case reflect.TypeOf(Database{}):
+		return map[string]any{"anyOf": []any{
+			objectSchema(t),
+			map[string]any{"type": "string", "description": "connection string, the same as dsn"},
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return objectSchema(t)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// objectSchema returns the schema of the struct t, whose properties are its fields with a
+// mapstructure tag.
+func objectSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("mapstructure"); name != "" {
+			properties[name] = schemaOf(t.Field(i).Type)
+		}
+	}
+
+	return map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+}
+
+// caseVariants returns values along with their upper case version, because the values of the
+// types with a Set method are case insensitive.
+func caseVariants(values []string) []string {
+	result := make([]string, 0, 2*len(values))
+	for _, each := range values {
+		result = append(result, each)
+		if upper := strings.ToUpper(each); upper != each {
+			result = append(result, upper)
+		}
+	}
+
+	return result
+}
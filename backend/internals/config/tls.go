@@ -0,0 +1,157 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// TLS12 is the TLS 1.2 version, the minimum one allowed.
+	TLS12 = "1.2"
+	// TLS13 is the TLS 1.3 version.
+	TLS13 = "1.3"
+)
+
+var (
+	// ErrTLSFilesRequired is used when only one of the certificate and the key of the server is set.
+	ErrTLSFilesRequired = errors.New("both the certificate and the key files are required to enable tls")
+	// ErrTLSVersionNotAllowed is used when the minimum TLS version is not 1.2 nor 1.3.
+	ErrTLSVersionNotAllowed = errors.New("tls min version must be " + TLS12 + " or " + TLS13)
+	// ErrCipherSuiteNotAllowed is used when a cipher suite is unknown or insecure.
+	ErrCipherSuiteNotAllowed = errors.New("cipher suite not allowed")
+	// ErrClientCANotFound is used when the client CA file has no certificates.
+	ErrClientCANotFound = errors.New("no certificates found in the client ca file")
+)
+
+// TLS is the configuration of the encryption of the HTTP server. It is enabled when the certificate and
+// the key are set. The files are read again whenever they change, so certificates can be renewed without
+// restarting the API.
+type TLS struct {
+	// CertFile is the PEM file with the certificate of the server, followed by the intermediate ones.
+	CertFile string `json:"cert_file" yaml:"cert_file" mapstructure:"cert_file"`
+	// KeyFile is the PEM file with the private key of the certificate of the server.
+	KeyFile string `json:"key_file" yaml:"key_file" mapstructure:"key_file"`
+	// MinVersion is the minimum TLS version accepted, 1.2 or 1.3. It is 1.2 if empty.
+	MinVersion string `json:"min_version" yaml:"min_version" mapstructure:"min_version"`
+	// CipherSuites are the names of the cipher suites accepted for TLS 1.2, in order of preference, like
+	// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The TLS 1.3 ones are not configurable. The secure defaults
+	// of Go are used if it is empty.
+	CipherSuites []string `json:"cipher_suites" yaml:"cipher_suites" mapstructure:"cipher_suites"`
+	// ClientCAFile is the PEM file with the certificates used to verify the clients. If it is set, the
+	// clients must present a certificate signed by one of them (mutual TLS).
+	ClientCAFile string `json:"client_ca_file" yaml:"client_ca_file" mapstructure:"client_ca_file"`
+}
+
+// Enabled returns true if the server must be served over TLS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// Check returns an error if the configuration is not valid, without reading the files.
+func (t TLS) Check() error {
+	if !t.Enabled() {
+		return nil
+	} else if t.CertFile == "" || t.KeyFile == "" {
+		return ErrTLSFilesRequired
+	}
+
+	if _, err := ParseTLSVersion(t.MinVersion); err != nil {
+		return err
+	}
+
+	_, err := ParseCipherSuites(t.CipherSuites)
+
+	return err
+}
+
+// Load reads the files and returns the tls.Config of the server.
+func (t TLS) Load() (*tls.Config, error) {
+	version, err := ParseTLSVersion(t.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	suites, err := ParseCipherSuites(t.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   version,
+		CipherSuites: suites,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if t.ClientCAFile != "" {
+		content, err := os.ReadFile(t.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		result.ClientCAs = x509.NewCertPool()
+		if !result.ClientCAs.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("%w %s", ErrClientCANotFound, t.ClientCAFile)
+		}
+
+		result.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return result, nil
+}
+
+// Files returns the files the configuration is read from.
+func (t TLS) Files() []string {
+	files := []string{t.CertFile, t.KeyFile}
+	if t.ClientCAFile != "" {
+		files = append(files, t.ClientCAFile)
+	}
+
+	return files
+}
+
+// ParseTLSVersion returns the tls version named s, being 1.2 if s is empty.
+func ParseTLSVersion(s string) (uint16, error) {
+	switch s {
+	case "", TLS12:
+		return tls.VersionTLS12, nil
+	case TLS13:
+		return tls.VersionTLS13, nil
+	}
+
+	return 0, fmt.Errorf("%w, got %q", ErrTLSVersionNotAllowed, s)
+}
+
+// ParseCipherSuites returns the ids of the cipher suites named names. Only the ones which
+// tls.CipherSuites considers secure are allowed.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	ids := make(map[string]uint16)
+	for _, each := range tls.CipherSuites() {
+		ids[each.Name] = each.ID
+	}
+
+	result := make([]uint16, len(names))
+	for i, each := range names {
+		id, ok := ids[strings.ToUpper(strings.TrimSpace(each))]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrCipherSuiteNotAllowed, each)
+		}
+
+		result[i] = id
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSCheck(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       TLS
+		want        error
+	}{
+		{
+			description: "disabled",
+			input:       TLS{MinVersion: "1.0"},
+		},
+		{
+			description: "enabled",
+			input:       TLS{CertFile: "server.pem", KeyFile: "server-key.pem", MinVersion: TLS13},
+		},
+		{
+			description: "key missing",
+			input:       TLS{CertFile: "server.pem"},
+			want:        ErrTLSFilesRequired,
+		},
+		{
+			description: "old version",
+			input:       TLS{CertFile: "server.pem", KeyFile: "server-key.pem", MinVersion: "1.1"},
+			want:        ErrTLSVersionNotAllowed,
+		},
+		{
+			description: "insecure cipher suite",
+			input: TLS{
+				CertFile: "server.pem", KeyFile: "server-key.pem", CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+			},
+			want: ErrCipherSuiteNotAllowed,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			assert.ErrorIs(t, each.input.Check(), each.want)
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	for input, want := range map[string]uint16{"": tls.VersionTLS12, TLS12: tls.VersionTLS12, TLS13: tls.VersionTLS13} {
+		got, err := ParseTLSVersion(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	got, err := ParseCipherSuites([]string{"tls_ecdhe_rsa_with_aes_128_gcm_sha256", " TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256}, got)
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+const (
+	pluginName = "go-home:metrics"
+	startKey   = "go-home:metrics:start"
+
+	// unknownTable is the table of the queries whose table is not known, like some raw queries.
+	unknownTable = "unknown"
+)
+
+// queryMetrics are the metrics of the queries, which are nil until Configure enables them.
+var queryMetrics atomic.Pointer[queryCollectors]
+
+type queryCollectors struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func newQueryMetrics() *queryCollectors {
+	return &queryCollectors{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Latency of the queries, by operation and table.",
+			Buckets:   durationBuckets,
+		}, []string{"operation", "table"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "db",
+			Name:      "query_errors_total",
+			Help:      "Amount of queries which failed, by operation and table. Not finding a record is not an error.",
+		}, []string{"operation", "table"}),
+	}
+}
+
+// Plugin is the gorm plugin which records the latency and the errors of the queries by operation
+// and table, using callbacks around the ones of gorm.
+type Plugin struct{}
+
+// Name returns the name of the plugin.
+func (Plugin) Name() string {
+	return pluginName
+}
+
+// Initialize registers the callbacks of the plugin in db.
+func (Plugin) Initialize(db *gorm.DB) error {
+	for _, each := range []struct {
+		operation string
+		before    func(string, func(*gorm.DB)) error
+		after     func(string, func(*gorm.DB)) error
+	}{
+		{"create", db.Callback().Create().Before("gorm:create").Register, db.Callback().Create().After("gorm:create").Register},
+		{"query", db.Callback().Query().Before("gorm:query").Register, db.Callback().Query().After("gorm:query").Register},
+		{"update", db.Callback().Update().Before("gorm:update").Register, db.Callback().Update().After("gorm:update").Register},
+		{"delete", db.Callback().Delete().Before("gorm:delete").Register, db.Callback().Delete().After("gorm:delete").Register},
+		{"row", db.Callback().Row().Before("gorm:row").Register, db.Callback().Row().After("gorm:row").Register},
+		{"raw", db.Callback().Raw().Before("gorm:raw").Register, db.Callback().Raw().After("gorm:raw").Register},
+	} {
+		if err := each.before(pluginName+":before_"+each.operation, before); err != nil {
+			return err
+		}
+
+		if err := each.after(pluginName+":after_"+each.operation, after(each.operation)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		q := queryMetrics.Load()
+		if q == nil {
+			return
+		}
+
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = unknownTable
+		}
+
+		q.duration.WithLabelValues(operation, table).Observe(time.Since(value.(time.Time)).Seconds())
+
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			q.errors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute is the route of the requests which don't match any route, so the paths requested
+// don't make the amount of series grow without limit.
+const unmatchedRoute = "unmatched"
+
+// httpMetrics are the metrics of the responses, which are nil until Configure enables them.
+var httpMetrics atomic.Pointer[httpCollectors]
+
+type httpCollectors struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+}
+
+func newHTTPMetrics() *httpCollectors {
+	return &httpCollectors{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Amount of requests responded, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests, by method and route.",
+			Buckets:   durationBuckets,
+		}, []string{"method", "route"}),
+		size: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "response_size_bytes",
+			Help:      "Size of the bodies of the responses, by method and route.",
+			Buckets:   sizeBuckets,
+		}, []string{"method", "route"}),
+	}
+}
+
+// Middleware records the count, the latency and the size of the responses by the route template,
+// like /food/categories/:category, instead of the path requested. It does nothing if the metrics
+// of the responses are not enabled.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h := httpMetrics.Load()
+		if h == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+
+		h.requests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		h.duration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+		h.size.WithLabelValues(c.Request.Method, route).Observe(float64(size))
+	}
+}
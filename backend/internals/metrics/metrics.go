@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+const (
+	// Namespace is the prefix of the names of the metrics of the API.
+	Namespace = "go_home"
+	// DBName is the label of the database in the metrics of the pool of connections.
+	DBName = "go-home"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	// durationBuckets are the buckets of the latencies, from 5ms to 10s.
+	durationBuckets = prometheus.DefBuckets
+	// sizeBuckets are the buckets of the sizes of the responses, from 100B to 10MB.
+	sizeBuckets = prometheus.ExponentialBuckets(100, 10, 6)
+)
+
+// Configure registers the metrics enabled by m, instrumenting db with the Plugin if the metrics of
+// the database are enabled. Nothing is registered if the metrics are disabled.
+func Configure(m config.Metrics, db *gorm.DB) error {
+	if !m.Enabled {
+		return nil
+	}
+
+	next := prometheus.NewRegistry()
+
+	if m.Runtime {
+		if err := register(next, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+			return err
+		}
+	}
+
+	if m.HTTP {
+		h := newHTTPMetrics()
+		if err := register(next, h.requests, h.duration, h.size); err != nil {
+			return err
+		}
+
+		httpMetrics.Store(h)
+	}
+
+	if m.Database {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+
+		q := newQueryMetrics()
+		if err = register(next, q.duration, q.errors, collectors.NewDBStatsCollector(sqlDB, DBName)); err != nil {
+			return err
+		}
+
+		queryMetrics.Store(q)
+
+		if _, ok := db.Config.Plugins[pluginName]; !ok {
+			if err = db.Use(Plugin{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	registry = next
+
+	return nil
+}
+
+// Handler returns the handler which serves the metrics registered by Configure.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func register(r *prometheus.Registry, cs ...prometheus.Collector) error {
+	for _, each := range cs {
+		if err := r.Register(each); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
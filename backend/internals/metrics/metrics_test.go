@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MrTimeout/go-home/backend/internals/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	return w.Body.String()
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() {
+		httpMetrics.Store(nil)
+		queryMetrics.Store(nil)
+	})
+
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=home"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	t.Run("nothing is registered if the metrics are disabled", func(t *testing.T) {
+		assert.NoError(t, Configure(config.Metrics{HTTP: true, Runtime: true}, db))
+		assert.Nil(t, httpMetrics.Load())
+	})
+
+	t.Run("only the metrics enabled are registered", func(t *testing.T) {
+		require.NoError(t, Configure(config.Metrics{Enabled: true, Database: true}, db))
+
+		got := scrape(t)
+
+		assert.Contains(t, got, "go_sql_max_open_connections")
+		assert.NotContains(t, got, "go_goroutines")
+		assert.Nil(t, httpMetrics.Load())
+	})
+
+	t.Run("responses are labelled by route template", func(t *testing.T) {
+		require.NoError(t, Configure(config.Metrics{Enabled: true, HTTP: true, Runtime: true}, db))
+
+		router := gin.New()
+		router.Use(Middleware())
+		router.GET("/food/categories/:category", func(c *gin.Context) {
+			c.String(http.StatusOK, "fruits")
+		})
+
+		for _, each := range []string{"/food/categories/fruits", "/food/categories/vegetables", "/unknown"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, each, nil))
+		}
+
+		got := scrape(t)
+
+		assert.Contains(t, got, `go_home_http_requests_total{method="GET",route="/food/categories/:category",status="200"} 2`)
+		assert.Contains(t, got, `go_home_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+		assert.Contains(t, got, `go_home_http_response_size_bytes_sum{method="GET",route="/food/categories/:category"} 12`)
+		assert.Contains(t, got, "go_goroutines")
+		assert.NotContains(t, got, "/food/categories/fruits")
+	})
+
+	t.Run("queries are labelled by operation and table", func(t *testing.T) {
+		require.NoError(t, Configure(config.Metrics{Enabled: true, Database: true}, db))
+
+		var rows []map[string]any
+		db.Table("categories").Find(&rows)
+		db.Table("categories").Where("name = ?", "fruits").Delete(nil)
+
+		got := scrape(t)
+
+		assert.Contains(t, got, `go_home_db_query_duration_seconds_count{operation="query",table="categories"} 1`)
+		assert.Contains(t, got, `go_home_db_query_duration_seconds_count{operation="delete",table="categories"} 1`)
+	})
+}
@@ -0,0 +1,468 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	// Table is the table which stores the versions of the migrations applied.
+	Table = "schema_migrations"
+	// VersionFormat is the layout of the timestamps used as versions of the migrations.
+	VersionFormat = "20060102150405"
+
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+
+	// lockKey is the key of the PostgreSQL advisory lock held while migrating, so two
+	// instances never migrate the same database at the same time.
+	lockKey int64 = 0x676f686f6d65
+	// duplicateTable is the PostgreSQL error code of the tables created when they already exist.
+	duplicateTable = "42P07"
+	// undefinedTable is the PostgreSQL error code of the queries of tables which don't exist.
+	undefinedTable = "42P01"
+	// unlockTimeout is how long releasing the advisory lock is waited for.
+	unlockTimeout = 5 * time.Second
+)
+
+var (
+	// ErrInvalidFileName is used when a migration file is not named <version>_<name>.(up|down).sql
+	ErrInvalidFileName = errors.New("invalid migration file name")
+	// ErrMissingFile is used when a migration has only the up or the down file.
+	ErrMissingFile = errors.New("migration must have both up and down files")
+	// ErrDuplicatedVersion is used when two migrations have the same version.
+	ErrDuplicatedVersion = errors.New("duplicated migration version")
+	// ErrUnknownVersion is used when a version applied in the database has no migration files.
+	ErrUnknownVersion = errors.New("applied migration version has no migration files")
+	// ErrInvalidName is used when the name of a new migration is empty or has characters
+	// other than letters, digits and underscores.
+	ErrInvalidName = errors.New("migration name can only contain letters, digits and underscores")
+	// ErrPendingMigrations is used when the database has migrations which were not applied.
+	ErrPendingMigrations = errors.New("there are pending migrations, run go-home migrate up")
+	// ErrNoSuchVersion is used when the version to baseline is not the one of any migration.
+	ErrNoSuchVersion = errors.New("there is no migration with that version")
+	// ErrBaselineRequired is used when the first migration fails because its tables already exist,
+	// which happens to the databases created before the migrations.
+	ErrBaselineRequired = errors.New("the tables already exist, run go-home migrate baseline " +
+		"if the database was created before the migrations")
+
+	//go:embed sql/*.sql
+	embedded embed.FS
+
+	fileNameRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	nameRegex     = regexp.MustCompile(`^\w+$`)
+)
+
+// Migration is each of the versioned changes of the database schema.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String returns the version and the name of the migration.
+func (m Migration) String() string {
+	return strconv.FormatInt(m.Version, 10) + "_" + m.Name
+}
+
+// Status is a migration along with the moment it was applied, which is nil if it is pending.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Embedded returns the migrations embedded in the binary, sorted by version.
+func Embedded() ([]Migration, error) {
+	sub, err := fs.Sub(embedded, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(sub)
+}
+
+// Load reads the migrations of fsys, which must be named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, sorted by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration, len(entries)/2)
+
+	for _, each := range entries {
+		if each.IsDir() {
+			continue
+		}
+
+		match := fileNameRegex.FindStringSubmatch(each.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, each.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, each.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, each.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicatedVersion, version)
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, each := range byVersion {
+		if strings.TrimSpace(each.Up) == "" || strings.TrimSpace(each.Down) == "" {
+			return nil, fmt.Errorf("%w: %s", ErrMissingFile, each)
+		}
+
+		result = append(result, *each)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
+
+// Create writes the empty up and down files of a new migration into dir, using now as its
+// version. It returns the paths of the files created.
+func Create(dir, name string, now time.Time) ([]string, error) {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+	if !nameRegex.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+
+	var (
+		base  = filepath.Join(dir, now.UTC().Format(VersionFormat)+"_"+name)
+		paths = []string{base + upSuffix, base + downSuffix}
+	)
+
+	for _, each := range paths {
+		f, err := os.OpenFile(each, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = fmt.Fprintf(f, "-- %s\n", name)
+		if cErr := f.Close(); err == nil {
+			err = cErr
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
+
+// Migrator applies and rolls back the migrations of a database.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New returns a migrator of the database db with the migrations passed, which must be
+// sorted by version.
+func New(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Status returns all the migrations, along with when they were applied.
+func (m *Migrator) Status() (result []Status, err error) {
+	err = m.locked(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		result = m.status(applied)
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Pending returns the migrations which were not applied yet.
+func (m *Migrator) Pending() ([]Migration, error) {
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	return pending(status), nil
+}
+
+// ReadPending returns the migrations which were not applied yet, only reading the table of the
+// migrations applied: the lock is not taken and the table is not created, so it can be called
+// as often as the readiness probe. All the migrations are pending if the table doesn't exist.
+func (m *Migrator) ReadPending() ([]Migration, error) {
+	var pgErr *pgconn.PgError
+
+	applied, err := appliedVersions(m.db)
+	if errors.As(err, &pgErr) && pgErr.Code == undefinedTable {
+		applied, err = nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pending(m.status(applied)), nil
+}
+
+// status returns the migrations along with when they were applied, taken from applied.
+func (m *Migrator) status(applied map[int64]time.Time) []Status {
+	result := make([]Status, 0, len(m.migrations))
+	for _, each := range m.migrations {
+		status := Status{Migration: each}
+		if appliedAt, ok := applied[each.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+
+		result = append(result, status)
+	}
+
+	return result
+}
+
+func pending(status []Status) []Migration {
+	var result []Migration
+	for _, each := range status {
+		if each.AppliedAt == nil {
+			result = append(result, each.Migration)
+		}
+	}
+
+	return result
+}
+
+// Up applies the pending migrations in order, each of them in its own transaction. Only the
+// first steps migrations are applied, or all of them if steps is not positive.
+func (m *Migrator) Up(steps int) (result []Migration, err error) {
+	err = m.locked(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, each := range m.migrations {
+			if _, ok := applied[each.Version]; ok {
+				continue
+			} else if steps > 0 && len(result) == steps {
+				break
+			}
+
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(each.Up).Error; err != nil {
+					return err
+				}
+
+				return record(tx, each)
+			}); err != nil {
+				var pgErr *pgconn.PgError
+				if len(applied) == 0 && errors.As(err, &pgErr) && pgErr.Code == duplicateTable {
+					return fmt.Errorf("applying migration %s: %w: %s", each, ErrBaselineRequired, pgErr.Message)
+				}
+
+				return fmt.Errorf("applying migration %s: %w", each, err)
+			}
+
+			result = append(result, each)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Baseline records the migrations up to version as applied without running them, for the
+// databases whose schema was created before the migrations, by the AutoMigrate of gorm. The
+// migrations already applied are skipped, and the first one is used if version is not positive.
+// The migrations after it are applied by Up as usual.
+func (m *Migrator) Baseline(version int64) (result []Migration, err error) {
+	if version <= 0 && len(m.migrations) > 0 {
+		version = m.migrations[0].Version
+	}
+
+	found := false
+	for _, each := range m.migrations {
+		found = found || each.Version == version
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %d", ErrNoSuchVersion, version)
+	}
+
+	err = m.locked(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, each := range m.migrations {
+			if each.Version > version {
+				break
+			} else if _, ok := applied[each.Version]; ok {
+				continue
+			}
+
+			if err := record(conn, each); err != nil {
+				return fmt.Errorf("recording migration %s: %w", each, err)
+			}
+
+			result = append(result, each)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Down rolls back the last steps migrations applied, from the newest to the oldest, each
+// of them in its own transaction. Only the last one is rolled back if steps is not positive.
+func (m *Migrator) Down(steps int) (result []Migration, err error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	byVersion := make(map[int64]Migration, len(m.migrations))
+	for _, each := range m.migrations {
+		byVersion[each.Version] = each
+	}
+
+	err = m.locked(func(conn *gorm.DB) error {
+		var versions []int64
+
+		txx := conn.Table(Table).Order("version DESC").Limit(steps).Pluck("version", &versions)
+		if txx.Error != nil {
+			return txx.Error
+		}
+
+		for _, version := range versions {
+			each, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+			}
+
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(each.Down).Error; err != nil {
+					return err
+				}
+
+				return tx.Exec("DELETE FROM "+Table+" WHERE version = ?", each.Version).Error
+			}); err != nil {
+				return fmt.Errorf("rolling back migration %s: %w", each, err)
+			}
+
+			result = append(result, each)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// locked runs fn holding the advisory lock of the migrations, in a single connection of the
+// pool because the advisory locks belong to the session. It also creates the table of the
+// migrations applied if it doesn't exist. The lock is released even if the context of the
+// database is done. Otherwise, the connection is closed instead of returned to the pool, so
+// its session doesn't keep the lock.
+func (m *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) (err error) {
+		sqlConn, _ := conn.Statement.ConnPool.(*sql.Conn)
+
+		if err = conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			discard(sqlConn)
+			return err
+		}
+
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+			defer cancel()
+
+			if uErr := conn.WithContext(ctx).Exec("SELECT pg_advisory_unlock(?)", lockKey).Error; uErr != nil {
+				discard(sqlConn)
+				if err == nil {
+					err = fmt.Errorf("releasing the lock of the migrations: %w", uErr)
+				}
+			}
+		}()
+
+		if err = conn.Exec(
+			"CREATE TABLE IF NOT EXISTS " + Table + "(" +
+				"version BIGINT PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMPTZ NOT NULL DEFAULT now())",
+		).Error; err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
+
+// discard closes the connection of the pool held by c, instead of returning it to the pool,
+// which ends its session along with the advisory locks it holds.
+func discard(c *sql.Conn) {
+	if c != nil {
+		c.Raw(func(any) error { return driver.ErrBadConn }) //nolint:errcheck
+	}
+}
+
+// record stores migration m as applied.
+func record(conn *gorm.DB, m Migration) error {
+	return conn.Exec("INSERT INTO "+Table+"(version, name) VALUES (?, ?)", m.Version, m.Name).Error
+}
+
+func appliedVersions(conn *gorm.DB) (map[int64]time.Time, error) {
+	var rows []struct {
+		Version   int64
+		AppliedAt time.Time
+	}
+
+	if err := conn.Table(Table).Select("version", "applied_at").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]time.Time, len(rows))
+	for _, each := range rows {
+		applied[each.Version] = each.AppliedAt
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,146 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestLoad(t *testing.T) {
+	for _, each := range []struct {
+		description string
+		input       fstest.MapFS
+		want        []Migration
+		wantErr     error
+	}{
+		{
+			description: "migrations sorted by version",
+			input: fstest.MapFS{
+				"2_second.up.sql":   {Data: []byte("CREATE INDEX b")},
+				"2_second.down.sql": {Data: []byte("DROP INDEX b")},
+				"1_first.up.sql":    {Data: []byte("CREATE TABLE a")},
+				"1_first.down.sql":  {Data: []byte("DROP TABLE a")},
+			},
+			want: []Migration{
+				{Version: 1, Name: "first", Up: "CREATE TABLE a", Down: "DROP TABLE a"},
+				{Version: 2, Name: "second", Up: "CREATE INDEX b", Down: "DROP INDEX b"},
+			},
+		},
+		{
+			description: "invalid file name",
+			input:       fstest.MapFS{"first.up.sql": {Data: []byte("CREATE TABLE a")}},
+			wantErr:     ErrInvalidFileName,
+		},
+		{
+			description: "down file missing",
+			input:       fstest.MapFS{"1_first.up.sql": {Data: []byte("CREATE TABLE a")}},
+			wantErr:     ErrMissingFile,
+		},
+		{
+			description: "same version with different names",
+			input: fstest.MapFS{
+				"1_first.up.sql":   {Data: []byte("CREATE TABLE a")},
+				"1_other.down.sql": {Data: []byte("DROP TABLE a")},
+			},
+			wantErr: ErrDuplicatedVersion,
+		},
+	} {
+		t.Run(each.description, func(t *testing.T) {
+			got, err := Load(each.input)
+
+			assert.ErrorIs(t, err, each.wantErr)
+			assert.Equal(t, each.want, got)
+		})
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	got, err := Embedded()
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, got)
+
+	for i := 1; i < len(got); i++ {
+		assert.Less(t, got[i-1].Version, got[i].Version)
+	}
+}
+
+func TestCompleteAutomigrateSchema(t *testing.T) {
+	got, err := Embedded()
+	require.NoError(t, err)
+	require.Greater(t, len(got), 2)
+
+	assert.Equal(t, "create_catalog", got[0].Name)
+	assert.Equal(t, "complete_automigrate_schema", got[1].Name, "it runs after a baseline and before the rest")
+
+	for _, each := range strings.Split(got[1].Up, ";") {
+		if statement := strings.TrimSpace(each); strings.Contains(statement, "CREATE") || strings.Contains(statement, "ADD") {
+			assert.Contains(t, statement, "IF NOT EXISTS", "every statement must be idempotent")
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		now = time.Date(2022, time.October, 2, 10, 30, 0, 0, time.UTC)
+	)
+
+	got, err := Create(dir, "Add Unit Weight", now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "20221002103000_add_unit_weight.up.sql"),
+		filepath.Join(dir, "20221002103000_add_unit_weight.down.sql"),
+	}, got)
+
+	migrations, err := Load(os.DirFS(dir))
+	assert.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 20221002103000, Name: "add_unit_weight", Up: "-- add_unit_weight\n", Down: "-- add_unit_weight\n"},
+	}, migrations)
+
+	_, err = Create(dir, "add_unit_weight", now)
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	_, err = Create(dir, "drop;table", now)
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
+
+func TestReadPending(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=home"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var queries []string
+	record := func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:query", record))
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:raw", record))
+
+	embedded, err := Embedded()
+	require.NoError(t, err)
+
+	got, err := New(db, embedded).ReadPending()
+
+	assert.NoError(t, err)
+	assert.Equal(t, embedded, got, "all the migrations are pending if none was applied")
+	assert.Equal(t, []string{`SELECT "version","applied_at" FROM "` + Table + `"`}, queries, "neither the lock nor the table are used")
+}
+
+func TestBaselineUnknownVersion(t *testing.T) {
+	embedded, err := Embedded()
+	require.NoError(t, err)
+
+	_, err = New(nil, embedded).Baseline(1)
+
+	assert.ErrorIs(t, err, ErrNoSuchVersion)
+}
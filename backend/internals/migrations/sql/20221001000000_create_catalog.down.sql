@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS food_unit_varieties;
+DROP TABLE IF EXISTS food_units;
+DROP TABLE IF EXISTS food_subcategories;
+DROP TABLE IF EXISTS food_categories;
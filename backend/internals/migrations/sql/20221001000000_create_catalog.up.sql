@@ -0,0 +1,52 @@
+-- create_catalog creates the tables of the food catalog:
+-- categories > subcategories > units > varieties.
+-- The databases created by the AutoMigrate of previous versions already have the categories,
+-- subcategories and units, so this migration must be recorded as applied with go-home migrate
+-- baseline instead. Their missing deleted_at columns and varieties are added by
+-- complete_automigrate_schema.
+CREATE TABLE food_categories(
+  food_category_id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  deleted_at TIMESTAMPTZ,
+  PRIMARY KEY (food_category_id)
+);
+
+CREATE INDEX idx_food_categories_deleted_at ON food_categories(deleted_at);
+
+CREATE TABLE food_subcategories(
+  food_subcategory_id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  food_category_id BIGINT NOT NULL REFERENCES food_categories(food_category_id) ON UPDATE CASCADE,
+  deleted_at TIMESTAMPTZ,
+  PRIMARY KEY (food_subcategory_id)
+);
+
+CREATE INDEX idx_food_subcategories_deleted_at ON food_subcategories(deleted_at);
+CREATE INDEX idx_food_subcategories_food_category_id ON food_subcategories(food_category_id);
+
+CREATE TABLE food_units(
+  food_unit_id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  food_subcategory_id BIGINT NOT NULL REFERENCES food_subcategories(food_subcategory_id) ON UPDATE CASCADE,
+  deleted_at TIMESTAMPTZ,
+  PRIMARY KEY (food_unit_id)
+);
+
+CREATE INDEX idx_food_units_deleted_at ON food_units(deleted_at);
+CREATE INDEX idx_food_units_food_subcategory_id ON food_units(food_subcategory_id);
+
+CREATE TABLE food_unit_varieties(
+  food_unit_variety_id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  img TEXT NOT NULL,
+  food_unit_id BIGINT NOT NULL REFERENCES food_units(food_unit_id) ON UPDATE CASCADE,
+  deleted_at TIMESTAMPTZ,
+  PRIMARY KEY (food_unit_variety_id)
+);
+
+CREATE INDEX idx_food_unit_varieties_deleted_at ON food_unit_varieties(deleted_at);
+CREATE INDEX idx_food_unit_varieties_food_unit_id ON food_unit_varieties(food_unit_id);
@@ -0,0 +1,3 @@
+-- complete_automigrate_schema only creates what create_catalog already has, so there is nothing
+-- to roll back: the tables and columns are dropped by the down of create_catalog.
+SELECT 1;
@@ -0,0 +1,26 @@
+-- complete_automigrate_schema adds what the AutoMigrate of previous versions didn't create to
+-- the databases baselined with go-home migrate baseline: the deleted_at columns of the trash
+-- and the food_unit_varieties table. Everything is created only if it doesn't exist, so it
+-- changes nothing in the databases created by create_catalog.
+ALTER TABLE food_categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE food_subcategories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE food_units ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_food_categories_deleted_at ON food_categories(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_food_subcategories_deleted_at ON food_subcategories(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_food_subcategories_food_category_id ON food_subcategories(food_category_id);
+CREATE INDEX IF NOT EXISTS idx_food_units_deleted_at ON food_units(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_food_units_food_subcategory_id ON food_units(food_subcategory_id);
+
+CREATE TABLE IF NOT EXISTS food_unit_varieties(
+  food_unit_variety_id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  img TEXT NOT NULL,
+  food_unit_id BIGINT NOT NULL REFERENCES food_units(food_unit_id) ON UPDATE CASCADE,
+  deleted_at TIMESTAMPTZ,
+  PRIMARY KEY (food_unit_variety_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_food_unit_varieties_deleted_at ON food_unit_varieties(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_food_unit_varieties_food_unit_id ON food_unit_varieties(food_unit_id);
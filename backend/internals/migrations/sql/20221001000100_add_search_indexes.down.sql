@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS food_unit_varieties_trgm_idx;
+DROP INDEX IF EXISTS food_unit_varieties_search_idx;
+DROP INDEX IF EXISTS food_units_trgm_idx;
+DROP INDEX IF EXISTS food_units_search_idx;
+DROP INDEX IF EXISTS food_subcategories_trgm_idx;
+DROP INDEX IF EXISTS food_subcategories_search_idx;
+DROP INDEX IF EXISTS food_categories_trgm_idx;
+DROP INDEX IF EXISTS food_categories_search_idx;
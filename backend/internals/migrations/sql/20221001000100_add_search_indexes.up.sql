@@ -0,0 +1,12 @@
+-- add_search_indexes creates the full text and trigram indexes used by /food/search.
+-- The indexed expression must be the same used by the queries of the search package.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX food_categories_search_idx ON food_categories USING GIN (to_tsvector('english', (name || ' ' || description)));
+CREATE INDEX food_categories_trgm_idx ON food_categories USING GIN ((name || ' ' || description) gin_trgm_ops);
+CREATE INDEX food_subcategories_search_idx ON food_subcategories USING GIN (to_tsvector('english', (name || ' ' || description)));
+CREATE INDEX food_subcategories_trgm_idx ON food_subcategories USING GIN ((name || ' ' || description) gin_trgm_ops);
+CREATE INDEX food_units_search_idx ON food_units USING GIN (to_tsvector('english', (name || ' ' || description)));
+CREATE INDEX food_units_trgm_idx ON food_units USING GIN ((name || ' ' || description) gin_trgm_ops);
+CREATE INDEX food_unit_varieties_search_idx ON food_unit_varieties USING GIN (to_tsvector('english', (name || ' ' || description)));
+CREATE INDEX food_unit_varieties_trgm_idx ON food_unit_varieties USING GIN ((name || ' ' || description) gin_trgm_ops);
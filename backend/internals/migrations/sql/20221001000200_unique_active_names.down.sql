@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS food_unit_varieties_name_key;
+DROP INDEX IF EXISTS food_units_name_key;
+DROP INDEX IF EXISTS food_subcategories_name_key;
+DROP INDEX IF EXISTS food_categories_name_key;
+
+ALTER TABLE food_categories ADD CONSTRAINT food_categories_name_key UNIQUE (name);
+ALTER TABLE food_subcategories ADD CONSTRAINT food_subcategories_name_key UNIQUE (name);
+ALTER TABLE food_units ADD CONSTRAINT food_units_name_key UNIQUE (name);
+ALTER TABLE food_unit_varieties ADD CONSTRAINT food_unit_varieties_name_key UNIQUE (name);
@@ -0,0 +1,11 @@
+-- unique_active_names only requires the names to be unique among the resources which are not
+-- in the trash, so the name of a deleted resource can be taken by a new one.
+ALTER TABLE food_categories DROP CONSTRAINT IF EXISTS food_categories_name_key;
+ALTER TABLE food_subcategories DROP CONSTRAINT IF EXISTS food_subcategories_name_key;
+ALTER TABLE food_units DROP CONSTRAINT IF EXISTS food_units_name_key;
+ALTER TABLE food_unit_varieties DROP CONSTRAINT IF EXISTS food_unit_varieties_name_key;
+
+CREATE UNIQUE INDEX food_categories_name_key ON food_categories(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX food_subcategories_name_key ON food_subcategories(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX food_units_name_key ON food_units(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX food_unit_varieties_name_key ON food_unit_varieties(name) WHERE deleted_at IS NULL;